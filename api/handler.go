@@ -35,27 +35,22 @@ func (h API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h API) insertWord(w http.ResponseWriter, r *http.Request) {
-	word := r.FormValue("word")
-	translate := r.FormValue("translate")
+	// word and translate may contain several accepted variants separated by semicolon
+	row := golearn.NewRow(r.FormValue("word"), r.FormValue("translate"), r.FormValue("category"))
 
-	if word == "" {
+	if row.Word == "" {
 		log.Printf("Failed to insert word: word is empty")
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	if translate == "" {
+	if row.Translate == "" {
 		log.Printf("Failed to insert word: translate is empty")
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	row := golearn.Row{
-		Word:      word,
-		Translate: translate,
-	}
-
-	err := h.Service.InsertWord(row)
+	err := h.Service.InsertWord(r.Context(), row)
 	if err != nil {
 		log.Printf("Failed to insert word: %v", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
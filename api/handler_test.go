@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInsertWord(t *testing.T) {
+	testCases := map[string]struct {
+		Form     url.Values
+		Row      golearn.Row
+		InsertOK bool
+		Code     int
+	}{
+		"word with several translations": {
+			Form: url.Values{"word": {"가다"}, "translate": {"идти; ходить"}, "category": {"verbs"}},
+			Row: golearn.Row{
+				Word:         "가다",
+				Translate:    "идти",
+				Category:     "verbs",
+				Translations: []string{"идти", "ходить"},
+			},
+			InsertOK: true,
+			Code:     http.StatusOK,
+		},
+		"word without category": {
+			Form:     url.Values{"word": {"가다"}, "translate": {"идти"}},
+			Row:      golearn.Row{Word: "가다", Translate: "идти"},
+			InsertOK: true,
+			Code:     http.StatusOK,
+		},
+		"empty word": {
+			Form: url.Values{"word": {" ; "}, "translate": {"идти"}},
+			Code: http.StatusBadRequest,
+		},
+		"empty translate": {
+			Form: url.Values{"word": {"가다"}},
+			Code: http.StatusBadRequest,
+		},
+		"failed insert": {
+			Form: url.Values{"word": {"가다"}, "translate": {"идти"}},
+			Row:  golearn.Row{Word: "가다", Translate: "идти"},
+			Code: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+			if tc.Row.Word != "" {
+				var err error
+				if !tc.InsertOK {
+					err = errors.New("failed")
+				}
+				dbService.On("InsertWord", mock.Anything, tc.Row).Return(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/word", strings.NewReader(tc.Form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			New(dbService).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.Code, w.Code)
+			if tc.Code == http.StatusOK {
+				var row golearn.Row
+				assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &row))
+				assert.Equal(t, tc.Row, row)
+			}
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
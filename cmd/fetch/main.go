@@ -10,7 +10,7 @@ import (
 	"time"
 
 	"github.com/sergeiten/golearn"
-	"github.com/sergeiten/golearn/mongo"
+	"github.com/sergeiten/golearn/storage"
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -27,8 +27,8 @@ func main() {
 	cfg := golearn.ConfigFromEnv()
 
 	for {
-		service, err := mongo.New(cfg)
-		golearn.LogFatal(err, "failed to create mongodb instance")
+		service, err := storage.New(cfg)
+		golearn.LogFatal(err, "failed to create database instance")
 		defer service.Close()
 
 		gsrv, err := getService()
@@ -41,7 +41,7 @@ func main() {
 			golearn.LogFatal(err, "failed to get spread sheet")
 		}
 
-		categories, err := service.GetCategories("")
+		categories, err := service.GetCategories(ctx, "")
 		if err != nil {
 			golearn.LogFatal(err, "failed to get categories")
 		}
@@ -50,7 +50,7 @@ func main() {
 			title := sheet.Properties.Title
 			sheetRange := title + "!A:B"
 			if inCategory(title, categories) {
-				err := service.DeleteWordsByCategory("", title)
+				err := service.DeleteWordsByCategory(ctx, "", title)
 				if err != nil {
 					golearn.LogFatal(err, "failed to delete words by category")
 				}
@@ -62,12 +62,9 @@ func main() {
 			}
 
 			for _, val := range values.Values {
-				w := golearn.Row{
-					Word:      val[0].(string),
-					Translate: val[1].(string),
-					Category:  title,
-				}
-				err := service.InsertWord(w)
+				// cells may contain several accepted variants separated by semicolon
+				w := golearn.NewRow(val[0].(string), val[1].(string), title)
+				err := service.InsertWord(ctx, w)
 				if err != nil {
 					golearn.LogFatal(err, "failed to insert word")
 				}
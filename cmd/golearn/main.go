@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -9,16 +10,18 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/sergeiten/golearn"
 	"github.com/sergeiten/golearn/api"
 	"github.com/sergeiten/golearn/kakaotalk"
-	"github.com/sergeiten/golearn/mongo"
+	"github.com/sergeiten/golearn/storage"
 	"github.com/sergeiten/golearn/telegram"
 )
 
 var port = flag.Int("port", 8888, "Server port")
+var db = flag.String("db", "", "Database driver: mongo, mysql, sqlite or memory, overrides DB_DRIVER env")
 
 func init() {
 	flag.Parse()
@@ -26,6 +29,14 @@ func init() {
 
 func main() {
 	cfg := golearn.ConfigFromEnv()
+	if *db != "" {
+		cfg.Database.Driver = *db
+	}
+	// demo with memory database asks bundled words if word list isn't set
+	if cfg.Database.Driver == storage.DriverMemory && cfg.Database.Words == "" {
+		cfg.Database.Words = "./words.json"
+	}
+
 	langFilename := fmt.Sprintf("./lang.%s.json", cfg.DefaultLanguage)
 	languageContent, err := ioutil.ReadFile(filepath.Clean(langFilename))
 	golearn.LogFatal(err, "failed to get language file content")
@@ -33,13 +44,17 @@ func main() {
 	language, err := golearn.GetLanguage(languageContent)
 	golearn.LogFatal(err, "failed to get language instance")
 
-	service, err := mongo.New(cfg)
-	golearn.LogFatal(err, "failed to create mongodb instance")
+	service, err := storage.New(cfg)
+	golearn.LogFatal(err, "failed to create database instance")
 	defer service.Close()
 
+	// telegram.DefaultTimeout is used if timeout isn't set
+	httpTimeout, _ := strconv.Atoi(os.Getenv("TELEGRAM_HTTP_TIMEOUT"))
+
 	telegramHTTP := telegram.NewHTTP(telegram.HTTPConfig{
-		API:   os.Getenv("TELEGRAM_API_URL"),
-		Token: os.Getenv("TELEGRAM_BOT_TOKEN"),
+		API:     os.Getenv("TELEGRAM_API_URL"),
+		Token:   os.Getenv("TELEGRAM_BOT_TOKEN"),
+		Timeout: time.Duration(httpTimeout) * time.Second,
 	})
 
 	cols, err := strconv.Atoi(os.Getenv("TELEGRAM_COLS_COUNT"))
@@ -48,24 +63,69 @@ func main() {
 		cols = 2 // default value
 	}
 
-	err = telegram.New(telegram.HandlerConfig{
+	telegramHandler := telegram.New(telegram.HandlerConfig{
 		DBService:       service,
 		HTTPService:     telegramHTTP,
 		Lang:            language,
 		DefaultLanguage: cfg.DefaultLanguage,
 		Token:           os.Getenv("TELEGRAM_BOT_TOKEN"),
 		ColsCount:       cols,
-	}).Serve()
+		Matcher:         golearn.NewMatcher(cfg.MaxDistance),
+		RecentWords:     cfg.RecentWords,
+		Secret:          os.Getenv("TELEGRAM_WEBHOOK_SECRET"),
+	})
+
+	// polling is used where bot has no public HTTPS endpoint for webhook
+	if os.Getenv("TELEGRAM_UPDATES") == "polling" {
+		timeout, err := strconv.Atoi(os.Getenv("TELEGRAM_POLL_TIMEOUT"))
+		if err != nil {
+			timeout = telegram.DefaultPollTimeout
+		}
 
-	golearn.LogFatal(err, "failed to start handler")
+		offsetFile := os.Getenv("TELEGRAM_OFFSET_FILE")
+		if offsetFile == "" {
+			offsetFile = "telegram.offset"
+		}
+
+		poller := telegram.NewPoller(telegram.PollerConfig{
+			Handler: telegramHandler,
+			HTTP:    telegramHTTP,
+			Offsets: telegram.NewFileOffset(offsetFile),
+			Timeout: timeout,
+		})
+
+		go func() {
+			err := poller.Run(context.Background())
+			golearn.LogFatal(err, "failed to poll telegram updates")
+		}()
+	} else {
+		// webhook is registered on startup if public URL of the app is known
+		if webhookURL := os.Getenv("TELEGRAM_WEBHOOK_URL"); webhookURL != "" {
+			webhookURL = strings.TrimRight(webhookURL, "/") + telegram.WebhookPath(os.Getenv("TELEGRAM_BOT_TOKEN"))
+			err = telegramHTTP.SetWebhook(context.Background(), webhookURL, os.Getenv("TELEGRAM_WEBHOOK_SECRET"))
+			golearn.LogFatal(err, "failed to set telegram webhook")
+		}
+
+		err = telegramHandler.Serve()
+		golearn.LogFatal(err, "failed to start handler")
+	}
 
 	err = api.New(service).Serve()
 	golearn.LogFatal(err, "failed to start serving telegram handler")
 
+	kakaoCols, err := strconv.Atoi(os.Getenv("KAKAO_COLS_COUNT"))
+	if err != nil {
+		golearn.LogPrint(err, "failed to get kakaotalk cols count")
+		kakaoCols = 2 // default value
+	}
+
 	err = kakaotalk.New(kakaotalk.Config{
 		Service:         service,
 		Lang:            language,
+		ColsCount:       kakaoCols,
 		DefaultLanguage: cfg.DefaultLanguage,
+		Matcher:         golearn.NewMatcher(cfg.MaxDistance),
+		RecentWords:     cfg.RecentWords,
 	}).Serve()
 	golearn.LogFatal(err, "failed to start serving kakaotalk handler")
 
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/mongo"
+	"github.com/sergeiten/golearn/storage"
+)
+
+const usage = `Usage: migrate <command>
+
+Commands:
+  status  show version of database and pending migrations
+  up      apply pending migrations
+
+Database is configured by DB_* variables, only mongo has migrations.
+Other drivers create their schema on startup.`
+
+// migrate is admin command for applying migrations of mongodb.
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal(usage)
+	}
+
+	cfg := golearn.ConfigFromEnv()
+	if cfg.Database.Driver != "" && cfg.Database.Driver != storage.DriverMongo {
+		log.Fatalf("%s database has no migrations", cfg.Database.Driver)
+	}
+
+	// migrations are applied by the command itself, so it can print them
+	cfg.Database.Migrate = false
+
+	service, err := mongo.New(cfg)
+	golearn.LogFatal(err, "failed to create database instance")
+	defer service.Close()
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "status":
+		version, err := service.Version(ctx)
+		golearn.LogFatal(err, "failed to get database version")
+
+		pending, err := service.Pending(ctx)
+		golearn.LogFatal(err, "failed to get pending migrations")
+
+		fmt.Printf("version: %d\n", version)
+		for _, m := range pending {
+			fmt.Printf("pending: %d %s\n", m.Version, m.Description)
+		}
+	case "up":
+		applied, err := service.Migrate(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied: %d %s\n", m.Version, m.Description)
+		}
+		golearn.LogFatal(err, "failed to apply migrations")
+
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
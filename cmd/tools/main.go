@@ -1,11 +1,12 @@
 package main
 
 import (
+	"context"
 	"math/rand"
 	"time"
 
 	"github.com/sergeiten/golearn"
-	"github.com/sergeiten/golearn/mongo"
+	"github.com/sergeiten/golearn/storage"
 )
 
 func main() {
@@ -16,8 +17,8 @@ func main() {
 			Name: "golearn",
 		},
 	}
-	service, err := mongo.New(cfg)
-	golearn.LogFatal(err, "failed to create mongodb instance")
+	service, err := storage.New(cfg)
+	golearn.LogFatal(err, "failed to create database instance")
 	defer service.Close()
 
 	state := golearn.State{
@@ -61,7 +62,7 @@ func main() {
 		if r == 1 {
 			isRight = false
 		}
-		err = service.InsertActivity(golearn.Activity{
+		err = service.InsertActivity(context.Background(), golearn.Activity{
 			UserID:    "177374215",
 			State:     state,
 			Answer:    "question word",
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/telegram"
+)
+
+const usage = `Usage: webhook <command>
+
+Commands:
+  info    show webhook status and the last delivery error
+  set     register TELEGRAM_WEBHOOK_URL as webhook with TELEGRAM_WEBHOOK_SECRET
+  delete  remove webhook, e.g. before running bot with polling`
+
+// webhook is admin command for diagnosing delivery of Telegram updates.
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal(usage)
+	}
+
+	token := os.Getenv("TELEGRAM_BOT_TOKEN")
+
+	telegramHTTP := telegram.NewHTTP(telegram.HTTPConfig{
+		API:   os.Getenv("TELEGRAM_API_URL"),
+		Token: token,
+	})
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "info":
+		info, err := telegramHTTP.WebhookInfo(ctx)
+		golearn.LogFatal(err, "failed to get webhook info")
+
+		fmt.Printf("url: %s\n", info.URL)
+		fmt.Printf("pending updates: %d\n", info.PendingUpdateCount)
+		fmt.Printf("max connections: %d\n", info.MaxConnections)
+		if info.LastErrorDate != 0 {
+			fmt.Printf("last error: %s at %s\n", info.LastErrorMessage, time.Unix(int64(info.LastErrorDate), 0).Format("2006-01-02 15:04:05"))
+		}
+	case "set":
+		webhookURL := os.Getenv("TELEGRAM_WEBHOOK_URL")
+		if webhookURL == "" {
+			log.Fatal("TELEGRAM_WEBHOOK_URL is empty")
+		}
+
+		webhookURL = strings.TrimRight(webhookURL, "/") + telegram.WebhookPath(token)
+		err := telegramHTTP.SetWebhook(ctx, webhookURL, os.Getenv("TELEGRAM_WEBHOOK_SECRET"))
+		golearn.LogFatal(err, "failed to set webhook")
+
+		fmt.Println("webhook has been set")
+	case "delete":
+		err := telegramHTTP.DeleteWebhook(ctx)
+		golearn.LogFatal(err, "failed to delete webhook")
+
+		fmt.Println("webhook has been deleted")
+	default:
+		log.Fatal(usage)
+	}
+}
@@ -3,6 +3,7 @@ package golearn
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 // Config ...
@@ -10,16 +11,47 @@ type Config struct {
 	Env             string   `json:"env"`
 	Database        Database `json:"database"`
 	DefaultLanguage string   `json:"default_language"`
+	MaxDistance     int      `json:"max_distance"`
+	// RecentWords is count of recently asked words which aren't asked again, zero disables it
+	RecentWords int `json:"recent_words"`
 }
 
 // Database ...
 type Database struct {
+	// Driver is name of storage backend, mongo is used if it's empty
+	Driver   string `json:"driver"`
 	Host     string `json:"host"`
 	Port     string `json:"port"`
 	User     string `json:"user"`
 	Name     string `json:"name"`
 	Password string `json:"password"`
-	Delay    int    `json:"delay"`
+	// URI is connection string of mongodb, host and port are ignored if it's set
+	URI string `json:"uri"`
+	// AuthSource is database where mongodb user is defined
+	AuthSource string `json:"auth_source"`
+	TLS        bool   `json:"tls"`
+	// MaxPoolSize limits count of open connections, driver default is used if it's zero
+	MaxPoolSize int `json:"max_pool_size"`
+	// Timeout of every database call in seconds, DefaultDatabaseTimeout is used if it's zero
+	Timeout int `json:"timeout"`
+	// ConnectRetries is count of attempts to connect after the failed first one
+	ConnectRetries int `json:"connect_retries"`
+	// Migrate applies pending migrations of mongodb on startup
+	Migrate bool `json:"migrate"`
+	// Words is path of word list inserted into memory database on start, it's empty if it isn't set
+	Words string `json:"words"`
+}
+
+// DefaultDatabaseTimeout limits database call if timeout isn't configured.
+const DefaultDatabaseTimeout = 5 * time.Second
+
+// CallTimeout returns timeout of every database call.
+func (d Database) CallTimeout() time.Duration {
+	if d.Timeout > 0 {
+		return time.Duration(d.Timeout) * time.Second
+	}
+
+	return DefaultDatabaseTimeout
 }
 
 // ConfigFromEnv returns config based on environment variables
@@ -27,19 +59,51 @@ func ConfigFromEnv() *Config {
 	cfg := &Config{}
 
 	cfg.Env = os.Getenv("ENV")
+	cfg.Database.Driver = os.Getenv("DB_DRIVER")
 	cfg.Database.Host = os.Getenv("DB_HOST")
 	cfg.Database.Port = os.Getenv("DB_PORT")
 	cfg.Database.Name = os.Getenv("DB_NAME")
 	cfg.Database.User = os.Getenv("DB_USER")
 	cfg.Database.Password = os.Getenv("DB_PASSWORD")
 
-	delay, err := strconv.Atoi(os.Getenv("DB_DELAY"))
-	if err != nil {
-		LogPrint(err, "failed to convert delay env")
-	}
-	cfg.Database.Delay = delay
+	cfg.Database.URI = os.Getenv("DB_URI")
+	cfg.Database.AuthSource = os.Getenv("DB_AUTH_SOURCE")
+	cfg.Database.TLS = os.Getenv("DB_TLS") == "true"
+	cfg.Database.MaxPoolSize = intFromEnv("DB_MAX_POOL_SIZE")
+	cfg.Database.Timeout = intFromEnv("DB_TIMEOUT")
+	cfg.Database.ConnectRetries = intFromEnv("DB_CONNECT_RETRIES")
+	cfg.Database.Migrate = os.Getenv("DB_MIGRATE") == "true"
+	cfg.Database.Words = os.Getenv("DB_WORDS")
 
 	cfg.DefaultLanguage = os.Getenv("DEFAULT_LANGUAGE")
 
+	cfg.MaxDistance = DefaultMaxDistance
+	if maxDistance := os.Getenv("ANSWER_MAX_DISTANCE"); maxDistance != "" {
+		var err error
+		cfg.MaxDistance, err = strconv.Atoi(maxDistance)
+		if err != nil {
+			LogPrint(err, "failed to convert answer max distance env")
+			cfg.MaxDistance = DefaultMaxDistance
+		}
+	}
+
+	cfg.RecentWords = DefaultRecentWords
+	if os.Getenv("RECENT_WORDS") != "" {
+		cfg.RecentWords = intFromEnv("RECENT_WORDS")
+	}
+
 	return cfg
 }
+
+// intFromEnv returns number from environment variable, zero is returned if it isn't set or isn't a number.
+func intFromEnv(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(value)
+	LogPrintf(err, "failed to convert %s env", name)
+
+	return n
+}
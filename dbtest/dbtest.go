@@ -0,0 +1,681 @@
+// Package dbtest contains conformance tests which every implementation of golearn.DBService has to pass.
+package dbtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/stretchr/testify/assert"
+)
+
+// Factory returns service with empty database.
+// It's called for every test, so tests don't depend on each other.
+type Factory func(t *testing.T) golearn.DBService
+
+// Run runs conformance tests against services returned by factory.
+func Run(t *testing.T, factory Factory) {
+	tests := map[string]func(t *testing.T, db golearn.DBService){
+		"Users":                 testUsers,
+		"Questions":             testQuestions,
+		"OwnWords":              testOwnWords,
+		"RandomAnswers":         testRandomAnswers,
+		"Distractors":           testDistractors,
+		"States":                testStates,
+		"RecentWords":           testRecentWords,
+		"Categories":            testCategories,
+		"DeleteWordsByCategory": testDeleteWordsByCategory,
+		"Statistics":            testStatistics,
+		"StatisticsAtNewYear":   testStatisticsAtNewYear,
+		"Reviews":               testReviews,
+		"NextDueQuestion":       testNextDueQuestion,
+		"Boxes":                 testBoxes,
+		"Cancelled":             testCancelled,
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			db := factory(t)
+			defer db.Close()
+
+			test(t, db)
+		})
+	}
+}
+
+var testUser = golearn.User{
+	UserID:    "177374215",
+	Username:  "sergeiten",
+	Name:      "Sergei",
+	Mode:      golearn.ModePicking,
+	Category:  "category",
+	Direction: golearn.DirectionForward,
+}
+
+var testWords = []golearn.Row{
+	{Word: "origin word 1", Translate: "translated word 1", Category: "category"},
+	{Word: "origin word 2", Translate: "translated word 2", Category: "category"},
+	{Word: "origin word 3", Translate: "translated word 3", Category: "category"},
+	{Word: "origin word 4", Translate: "translated word 4", Category: "category"},
+	{Word: "origin word 5", Translate: "translated word 5", Category: ""},
+	{Word: "origin word 6", Translate: "translated word 6", Category: "category 2"},
+}
+
+func seedWords(t *testing.T, db golearn.DBService, words []golearn.Row) {
+	ctx := context.Background()
+
+	for _, w := range words {
+		if err := db.InsertWord(ctx, w); err != nil {
+			t.Fatalf("failed to insert word: %v", err)
+		}
+	}
+}
+
+// word returns row without id, ids are assigned by database and differ between implementations.
+func word(r golearn.Row) golearn.Row {
+	r.ID = 0
+	return r
+}
+
+func testUsers(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	exists, err := db.ExistUser(ctx, testUser)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	_, err = db.ExistUser(ctx, golearn.User{})
+	assert.NotNil(t, err)
+
+	_, err = db.GetUser(ctx, "")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, db.InsertUser(ctx, testUser))
+
+	exists, err = db.ExistUser(ctx, testUser)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	user, err := db.GetUser(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, testUser, user)
+
+	updated := testUser
+	updated.Username = "sergei"
+	updated.Name = "Sergei Ten"
+	assert.Nil(t, db.UpdateUser(ctx, updated))
+
+	assert.Nil(t, db.SetUserMode(ctx, testUser.UserID, golearn.ModeTyping))
+	assert.Nil(t, db.SetUserCategory(ctx, testUser.UserID, "category 2"))
+	assert.Nil(t, db.SetUserDirection(ctx, testUser.UserID, golearn.DirectionReverse))
+	assert.Nil(t, db.SetUserLatinInput(ctx, testUser.UserID, true))
+	assert.Nil(t, db.SetUserAnswers(ctx, testUser.UserID, 6))
+
+	updated.Mode = golearn.ModeTyping
+	updated.Category = "category 2"
+	updated.Direction = golearn.DirectionReverse
+	updated.LatinInput = true
+	updated.Answers = 6
+
+	user, err = db.GetUser(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, updated, user)
+}
+
+func testQuestions(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	seedWords(t, db, testWords)
+
+	w := golearn.Row{
+		Word:         "가다",
+		Translate:    "to go",
+		Category:     "verbs",
+		Spellings:    []string{"가다", "가요"},
+		Translations: []string{"to go", "to walk"},
+	}
+	assert.Nil(t, db.InsertWord(ctx, w))
+
+	row, err := db.RandomQuestion(ctx, testUser.UserID, "verbs", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, w, word(row))
+
+	for i := 0; i < 10; i++ {
+		row, err = db.RandomQuestion(ctx, testUser.UserID, "category", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "category", row.Category)
+	}
+
+	row, err = db.RandomQuestion(ctx, testUser.UserID, "", nil)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, row.Word)
+
+	// recently asked words are skipped
+	recent := []string{"origin word 1", "origin word 2", "origin word 3"}
+	for i := 0; i < 10; i++ {
+		row, err = db.RandomQuestion(ctx, testUser.UserID, "category", recent)
+		assert.Nil(t, err)
+		assert.Equal(t, "origin word 4", row.Word)
+	}
+
+	// recently asked word is asked again if there is no other one
+	row, err = db.RandomQuestion(ctx, testUser.UserID, "verbs", []string{"가다"})
+	assert.Nil(t, err)
+	assert.Equal(t, w, word(row))
+
+	_, err = db.RandomQuestion(ctx, testUser.UserID, "unknown", nil)
+	assert.Equal(t, golearn.ErrNoWords, err)
+}
+
+func testOwnWords(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	own := golearn.Row{Word: "오다", Translate: "to come", Category: "mine", Owner: testUser.UserID}
+	seedWords(t, db, []golearn.Row{own})
+
+	row, err := db.RandomQuestion(ctx, testUser.UserID, "mine", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, own, word(row))
+
+	_, err = db.RandomQuestion(ctx, "another user", "mine", nil)
+	assert.Equal(t, golearn.ErrNoWords, err)
+}
+
+func testRandomAnswers(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	seedWords(t, db, testWords)
+	seedWords(t, db, []golearn.Row{
+		{Word: "오다", Translate: "to come", Category: "mine", Owner: testUser.UserID},
+		{Word: "보다", Translate: "to see", Category: "theirs", Owner: "another user"},
+	})
+
+	q := testWords[0]
+
+	// there are 6 other words visible to user: 5 global and 1 own
+	testCases := map[string]struct {
+		Count    int
+		Expected int
+	}{
+		"only right answer": {
+			Count:    1,
+			Expected: 1,
+		},
+		"enough words": {
+			Count:    4,
+			Expected: 4,
+		},
+		"all words": {
+			Count:    7,
+			Expected: 7,
+		},
+		"not enough words": {
+			Count:    10,
+			Expected: 7,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				answers, err := db.RandomAnswers(ctx, testUser.UserID, q, tc.Count)
+				assert.Nil(t, err)
+
+				if !assert.Len(t, answers, tc.Expected) {
+					return
+				}
+				assert.Equal(t, q, word(answers[len(answers)-1]))
+
+				words := map[string]bool{}
+				for _, a := range answers {
+					assert.False(t, words[a.Word], "answer %q is repeated", a.Word)
+					assert.NotEqual(t, "another user", a.Owner)
+					words[a.Word] = true
+				}
+			}
+		})
+	}
+}
+
+func testDistractors(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	q := golearn.Row{Word: "가다", Translate: "идти", Category: "verbs"}
+	seedWords(t, db, []golearn.Row{
+		q,
+		{Word: "오다", Translate: "приходить", Category: "verbs"},
+		{Word: "보다", Translate: "видеть", Category: "verbs"},
+		{Word: "걷다", Translate: "идти", Category: "verbs"},
+		{Word: "집", Translate: "дом", Category: "nouns"},
+		{Word: "물", Translate: "вода", Category: "nouns"},
+	})
+
+	for i := 0; i < 10; i++ {
+		// words of the same category go first
+		answers, err := db.RandomAnswers(ctx, testUser.UserID, q, 3)
+		assert.Nil(t, err)
+		if assert.Len(t, answers, 3) {
+			assert.ElementsMatch(t, []string{"오다", "보다", "가다"}, []string{answers[0].Word, answers[1].Word, answers[2].Word})
+		}
+
+		// words of other categories are taken if there aren't enough of them,
+		// word with the same translation as the right one is skipped
+		answers, err = db.RandomAnswers(ctx, testUser.UserID, q, 5)
+		assert.Nil(t, err)
+		assert.Len(t, answers, 5)
+
+		for _, a := range answers[:len(answers)-1] {
+			assert.NotEqual(t, q.Translate, a.Translate)
+		}
+	}
+}
+
+func testStates(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	_, err := db.GetState(ctx, testUser.UserID)
+	assert.NotNil(t, err)
+
+	state := golearn.State{
+		ID:        "5c6e5a1b",
+		UserKey:   testUser.UserID,
+		Question:  testWords[0],
+		Answers:   []golearn.Row{testWords[0], testWords[1]},
+		Mode:      golearn.ModePicking,
+		Category:  "category",
+		Direction: golearn.DirectionForward,
+		Timestamp: 1550000000,
+	}
+
+	latest := state
+	latest.ID = "5c6e5a1c"
+	latest.Question = testWords[1]
+	latest.Timestamp = 1550000002
+
+	earlier := state
+	earlier.ID = "5c6e5a1d"
+	earlier.Timestamp = 1550000001
+
+	another := state
+	another.ID = "5c6e5a1e"
+	another.UserKey = "another user"
+	another.Timestamp = 1550000003
+
+	// the latest state is returned even if it isn't the last saved one
+	for _, s := range []golearn.State{state, latest, earlier, another} {
+		assert.Nil(t, db.SetState(ctx, s))
+	}
+
+	got, err := db.GetState(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, latest, got)
+
+	assert.Nil(t, db.ResetState(ctx, testUser.UserID))
+
+	_, err = db.GetState(ctx, testUser.UserID)
+	assert.NotNil(t, err)
+
+	// states of other users are kept
+	got, err = db.GetState(ctx, another.UserKey)
+	assert.Nil(t, err)
+	assert.Equal(t, another, got)
+
+	// new state is saved after reset
+	assert.Nil(t, db.SetState(ctx, state))
+
+	got, err = db.GetState(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, state, got)
+}
+
+func testRecentWords(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	words, err := db.RecentWords(ctx, testUser.UserID, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, words)
+
+	states := []golearn.State{
+		{Question: testWords[0], Timestamp: 1550000001},
+		{Question: testWords[1], Timestamp: 1550000003},
+		{Question: testWords[0], Timestamp: 1550000002},
+		// word being added to own collection isn't asked
+		{Question: golearn.Row{Word: "집"}, Step: golearn.StepTranslate, Timestamp: 1550000004},
+		{Question: testWords[2], Timestamp: 1550000005, UserKey: "another user"},
+	}
+
+	for _, s := range states {
+		if s.UserKey == "" {
+			s.UserKey = testUser.UserID
+		}
+		assert.Nil(t, db.SetState(ctx, s))
+	}
+
+	words, err = db.RecentWords(ctx, testUser.UserID, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{testWords[1].Word, testWords[0].Word}, words)
+
+	words, err = db.RecentWords(ctx, testUser.UserID, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{testWords[1].Word}, words)
+}
+
+func testCategories(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	categories, err := db.GetCategories(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Empty(t, categories)
+
+	seedWords(t, db, testWords)
+	seedWords(t, db, []golearn.Row{
+		{Word: "오다", Translate: "to come", Category: "mine", Owner: testUser.UserID},
+		{Word: "보다", Translate: "to see", Category: "theirs", Owner: "another user"},
+		{Word: "가다", Translate: "to go", Category: "category 2", Owner: testUser.UserID},
+	})
+
+	categories, err = db.GetCategories(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, []golearn.Category{
+		{Name: "category", Words: 4},
+		{Name: "category 2", Words: 2},
+		{Name: "mine", Words: 1},
+	}, categories)
+
+	categories, err = db.GetCategories(ctx, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []golearn.Category{
+		{Name: "category", Words: 4},
+		{Name: "category 2", Words: 1},
+	}, categories)
+}
+
+func testDeleteWordsByCategory(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	seedWords(t, db, testWords)
+	seedWords(t, db, []golearn.Row{
+		{Word: "오다", Translate: "to come", Category: "category", Owner: testUser.UserID},
+	})
+
+	assert.Nil(t, db.DeleteWordsByCategory(ctx, "", "category"))
+
+	categories, err := db.GetCategories(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, []golearn.Category{
+		{Name: "category", Words: 1},
+		{Name: "category 2", Words: 1},
+	}, categories)
+
+	assert.Nil(t, db.DeleteWordsByCategory(ctx, testUser.UserID, "category"))
+
+	categories, err = db.GetCategories(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, []golearn.Category{
+		{Name: "category 2", Words: 1},
+	}, categories)
+}
+
+func testStatistics(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	kst := time.FixedZone("KST", 9*60*60)
+
+	// statistics is requested for 22 Feb 2019, it's Friday of 8th ISO week
+	activities := []struct {
+		UserID  string
+		Date    time.Time
+		IsRight bool
+	}{
+		// today
+		{testUser.UserID, time.Date(2019, 2, 22, 0, 0, 0, 0, time.UTC), true},
+		{testUser.UserID, time.Date(2019, 2, 22, 23, 59, 59, 0, time.UTC), false},
+		// dates are compared in UTC, it's 22 Feb 16:00 UTC
+		{testUser.UserID, time.Date(2019, 2, 23, 1, 0, 0, 0, kst), true},
+		// the same week
+		{testUser.UserID, time.Date(2019, 2, 21, 23, 59, 59, 0, time.UTC), true},
+		{testUser.UserID, time.Date(2019, 2, 18, 0, 0, 0, 0, time.UTC), false},
+		{testUser.UserID, time.Date(2019, 2, 24, 23, 59, 59, 0, time.UTC), true},
+		// the same month, previous week
+		{testUser.UserID, time.Date(2019, 2, 17, 23, 59, 59, 0, time.UTC), true},
+		{testUser.UserID, time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), false},
+		// other months
+		{testUser.UserID, time.Date(2019, 1, 31, 23, 59, 59, 0, time.UTC), true},
+		{testUser.UserID, time.Date(2019, 1, 22, 10, 0, 0, 0, time.UTC), true},
+		{testUser.UserID, time.Date(2019, 1, 21, 10, 0, 0, 0, time.UTC), false},
+		{testUser.UserID, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		// the same day of previous year
+		{testUser.UserID, time.Date(2018, 2, 22, 10, 0, 0, 0, time.UTC), true},
+		// another user
+		{"another user", time.Date(2019, 2, 22, 10, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, a := range activities {
+		err := db.InsertActivity(ctx, golearn.Activity{
+			UserID:    a.UserID,
+			State:     golearn.State{UserKey: a.UserID, Question: testWords[0]},
+			Answer:    "answer",
+			IsRight:   a.IsRight,
+			Timestamp: a.Date,
+		})
+		if err != nil {
+			t.Fatalf("failed to insert activity: %v", err)
+		}
+	}
+
+	statistics, err := db.GetStatistics(ctx, testUser.UserID, 2019, 2, 8, 22)
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.Statistics{
+		Today: golearn.StatRow{Total: 3, Right: 2, Wrong: 1},
+		Week:  golearn.StatRow{Total: 6, Right: 4, Wrong: 2},
+		Month: golearn.StatRow{Total: 8, Right: 5, Wrong: 3},
+	}, statistics)
+
+	statistics, err = db.GetStatistics(ctx, testUser.UserID, 2019, 1, 4, 21)
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.Statistics{
+		Today: golearn.StatRow{Total: 1, Right: 0, Wrong: 1},
+		Week:  golearn.StatRow{Total: 2, Right: 1, Wrong: 1},
+		Month: golearn.StatRow{Total: 3, Right: 2, Wrong: 1},
+	}, statistics)
+
+	statistics, err = db.GetStatistics(ctx, "unknown user", 2019, 2, 8, 22)
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.Statistics{}, statistics)
+}
+
+func testStatisticsAtNewYear(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	// 30 Dec 2019 - 5 Jan 2020 is the first ISO week of 2020, 31 Dec 2018 - 6 Jan 2019 is the first week of 2019
+	dates := []time.Time{
+		time.Date(2019, 12, 29, 10, 0, 0, 0, time.UTC),
+		time.Date(2019, 12, 30, 10, 0, 0, 0, time.UTC),
+		time.Date(2019, 12, 31, 10, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 6, 10, 0, 0, 0, time.UTC),
+		time.Date(2019, 1, 2, 10, 0, 0, 0, time.UTC),
+		time.Date(2018, 12, 31, 10, 0, 0, 0, time.UTC),
+	}
+
+	for _, date := range dates {
+		err := db.InsertActivity(ctx, golearn.Activity{
+			UserID:    testUser.UserID,
+			State:     golearn.State{UserKey: testUser.UserID, Question: testWords[0]},
+			Answer:    "answer",
+			IsRight:   true,
+			Timestamp: date,
+		})
+		if err != nil {
+			t.Fatalf("failed to insert activity: %v", err)
+		}
+	}
+
+	statistics, err := db.GetStatistics(ctx, testUser.UserID, 2019, 12, 1, 31)
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.Statistics{
+		Today: golearn.StatRow{Total: 1, Right: 1},
+		Week:  golearn.StatRow{Total: 3, Right: 3},
+		Month: golearn.StatRow{Total: 3, Right: 3},
+	}, statistics)
+
+	statistics, err = db.GetStatistics(ctx, testUser.UserID, 2020, 1, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.Statistics{
+		Today: golearn.StatRow{Total: 1, Right: 1},
+		Week:  golearn.StatRow{Total: 3, Right: 3},
+		Month: golearn.StatRow{Total: 2, Right: 2},
+	}, statistics)
+
+	statistics, err = db.GetStatistics(ctx, testUser.UserID, 2019, 1, 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.Statistics{
+		Today: golearn.StatRow{Total: 1, Right: 1},
+		Week:  golearn.StatRow{Total: 2, Right: 2},
+		Month: golearn.StatRow{Total: 1, Right: 1},
+	}, statistics)
+}
+
+func testReviews(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	review, err := db.GetReview(ctx, testUser.UserID, testWords[0])
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.NewReview(testUser.UserID, testWords[0]), review)
+
+	review.Box = 2
+	review.Repetitions = 1
+	review.Interval = 1
+	review.Due = time.Date(2019, 2, 23, 10, 0, 0, 0, time.UTC)
+	assert.Nil(t, db.SetReview(ctx, review))
+
+	got, err := db.GetReview(ctx, testUser.UserID, testWords[0])
+	assert.Nil(t, err)
+	assert.True(t, review.Due.Equal(got.Due))
+	got.Due = review.Due
+	assert.Equal(t, review, got)
+
+	review.Box = 3
+	review.Repetitions = 2
+	assert.Nil(t, db.SetReview(ctx, review))
+
+	got, err = db.GetReview(ctx, testUser.UserID, testWords[0])
+	assert.Nil(t, err)
+	assert.Equal(t, 3, got.Box)
+	assert.Equal(t, 2, got.Repetitions)
+}
+
+func testNextDueQuestion(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	words := testWords[:2]
+	seedWords(t, db, words)
+
+	now := time.Date(2019, 2, 22, 10, 0, 0, 0, time.UTC)
+
+	// due review goes first
+	due := golearn.NewReview(testUser.UserID, words[1])
+	due.Due = now.Add(-time.Hour)
+	assert.Nil(t, db.SetReview(ctx, due))
+
+	row, err := db.NextDueQuestion(ctx, testUser.UserID, "category", now, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, words[1], word(row))
+
+	// recently asked due word is skipped
+	row, err = db.NextDueQuestion(ctx, testUser.UserID, "category", now, []string{words[1].Word})
+	assert.Nil(t, err)
+	assert.Equal(t, words[0], word(row))
+
+	// unseen word goes next
+	due.Due = now.Add(48 * time.Hour)
+	assert.Nil(t, db.SetReview(ctx, due))
+
+	row, err = db.NextDueQuestion(ctx, testUser.UserID, "category", now, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, words[0], word(row))
+
+	// recently asked unseen word is skipped
+	row, err = db.NextDueQuestion(ctx, testUser.UserID, "category", now, []string{words[0].Word})
+	assert.Nil(t, err)
+	assert.Equal(t, words[1], word(row))
+
+	// the closest review goes if every word is seen
+	later := golearn.NewReview(testUser.UserID, words[0])
+	later.Due = now.Add(72 * time.Hour)
+	assert.Nil(t, db.SetReview(ctx, later))
+
+	row, err = db.NextDueQuestion(ctx, testUser.UserID, "category", now, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, words[1], word(row))
+
+	row, err = db.NextDueQuestion(ctx, testUser.UserID, "category", now, []string{words[1].Word})
+	assert.Nil(t, err)
+	assert.Equal(t, words[0], word(row))
+
+	// recently asked words are asked again if there is no other one
+	row, err = db.NextDueQuestion(ctx, testUser.UserID, "category", now, []string{words[0].Word, words[1].Word})
+	assert.Nil(t, err)
+	assert.Equal(t, words[1], word(row))
+
+	_, err = db.NextDueQuestion(ctx, testUser.UserID, "unknown", now, nil)
+	assert.Equal(t, golearn.ErrNoWords, err)
+
+	// reviews of deleted words are skipped
+	deleted := golearn.Row{Word: "deleted word", Translate: "deleted translation", Category: "deleted"}
+	seedWords(t, db, []golearn.Row{deleted})
+
+	orphan := golearn.NewReview(testUser.UserID, deleted)
+	orphan.Due = now.Add(-2 * time.Hour)
+	assert.Nil(t, db.SetReview(ctx, orphan))
+	assert.Nil(t, db.DeleteWordsByCategory(ctx, "", "deleted"))
+
+	row, err = db.NextDueQuestion(ctx, testUser.UserID, "", now, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, words[1], word(row))
+
+	_, err = db.NextDueQuestion(ctx, testUser.UserID, "deleted", now, nil)
+	assert.Equal(t, golearn.ErrNoWords, err)
+}
+
+func testBoxes(t *testing.T, db golearn.DBService) {
+	ctx := context.Background()
+
+	for i, box := range []int{0, 1, 1, 3} {
+		review := golearn.NewReview(testUser.UserID, testWords[i])
+		review.Box = box
+		review.Due = time.Date(2019, 2, 22, 10, 0, 0, 0, time.UTC)
+		assert.Nil(t, db.SetReview(ctx, review))
+	}
+
+	boxes, err := db.GetBoxes(ctx, testUser.UserID)
+	assert.Nil(t, err)
+	assert.Equal(t, []golearn.Box{
+		{Number: 1, Words: 2},
+		{Number: 3, Words: 1},
+	}, boxes)
+}
+
+func testCancelled(t *testing.T, db golearn.DBService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NotNil(t, db.InsertWord(ctx, testWords[0]))
+	assert.NotNil(t, db.InsertUser(ctx, testUser))
+
+	_, err := db.GetUser(ctx, testUser.UserID)
+	assert.NotNil(t, err)
+
+	_, err = db.GetCategories(ctx, testUser.UserID)
+	assert.NotNil(t, err)
+
+	_, err = db.GetStatistics(ctx, testUser.UserID, 2019, 2, 8, 22)
+	assert.NotNil(t, err)
+
+	// nothing is saved by cancelled calls
+	categories, err := db.GetCategories(context.Background(), testUser.UserID)
+	assert.Nil(t, err)
+	assert.Empty(t, categories)
+}
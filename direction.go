@@ -0,0 +1,60 @@
+package golearn
+
+import "strings"
+
+// DirectionForward constant for questions showing the word and asking for its translation
+const DirectionForward = "forward"
+
+// DirectionReverse constant for questions showing the translation and asking for the word
+const DirectionReverse = "reverse"
+
+// DirectionMixed constant for questions with random direction
+const DirectionMixed = "mixed"
+
+// DefaultDirection returns direction of questions for user who hasn't picked any.
+// Typing mode asks to type the word by its translation, other modes ask for translation.
+func DefaultDirection(mode string) string {
+	if mode == ModeTyping {
+		return DirectionReverse
+	}
+	return DirectionForward
+}
+
+// QuestionDirection returns direction of new question for passed user.
+// coin is tossed to pick forward or reverse direction for users with mixed direction.
+func QuestionDirection(user User, coin func() bool) string {
+	switch user.Direction {
+	case DirectionForward, DirectionReverse:
+		return user.Direction
+	case DirectionMixed:
+		if coin() {
+			return DirectionForward
+		}
+		return DirectionReverse
+	default:
+		return DefaultDirection(user.Mode)
+	}
+}
+
+// Ask returns text of the question shown to user with all its accepted variants.
+func (s State) Ask() string {
+	if s.Direction == DirectionReverse {
+		return strings.Join(s.Question.TranslateVariants(), VariantsSeparator)
+	}
+	return strings.Join(s.Question.WordVariants(), VariantsSeparator)
+}
+
+// AnswerOf returns text of passed row which is expected as an answer in state direction.
+// The right answer is AnswerOf(s.Question), answer options are AnswerOf(s.Answers[i]).
+// Several accepted variants are joined, so they fit one button of picking keyboard.
+func (s State) AnswerOf(row Row) string {
+	return strings.Join(s.VariantsOf(row), VariantsSeparator)
+}
+
+// VariantsOf returns all accepted answers of passed row in state direction.
+func (s State) VariantsOf(row Row) []string {
+	if s.Direction == DirectionReverse {
+		return row.WordVariants()
+	}
+	return row.TranslateVariants()
+}
@@ -0,0 +1,112 @@
+package golearn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuestionDirection(t *testing.T) {
+	heads := func() bool { return true }
+	tails := func() bool { return false }
+
+	testCases := map[string]struct {
+		User     User
+		Coin     func() bool
+		Expected string
+	}{
+		"picking mode default direction": {
+			User:     User{Mode: ModePicking},
+			Coin:     heads,
+			Expected: DirectionForward,
+		},
+		"leitner mode default direction": {
+			User:     User{Mode: ModeLeitner},
+			Coin:     heads,
+			Expected: DirectionForward,
+		},
+		"typing mode default direction": {
+			User:     User{Mode: ModeTyping},
+			Coin:     heads,
+			Expected: DirectionReverse,
+		},
+		"forward direction in typing mode": {
+			User:     User{Mode: ModeTyping, Direction: DirectionForward},
+			Coin:     tails,
+			Expected: DirectionForward,
+		},
+		"reverse direction in picking mode": {
+			User:     User{Mode: ModePicking, Direction: DirectionReverse},
+			Coin:     heads,
+			Expected: DirectionReverse,
+		},
+		"mixed direction heads": {
+			User:     User{Mode: ModePicking, Direction: DirectionMixed},
+			Coin:     heads,
+			Expected: DirectionForward,
+		},
+		"mixed direction tails": {
+			User:     User{Mode: ModePicking, Direction: DirectionMixed},
+			Coin:     tails,
+			Expected: DirectionReverse,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, QuestionDirection(tc.User, tc.Coin))
+		})
+	}
+}
+
+func TestStateSides(t *testing.T) {
+	question := Row{
+		Word:      "question word",
+		Translate: "question translate",
+	}
+	option := Row{
+		Word:         "answer word",
+		Translate:    "answer translate",
+		Translations: []string{"answer translate", "answer meaning"},
+	}
+
+	testCases := map[string]struct {
+		Direction string
+		Ask       string
+		Answer    string
+		Option    string
+	}{
+		"forward": {
+			Direction: DirectionForward,
+			Ask:       "question word",
+			Answer:    "question translate",
+			Option:    "answer translate; answer meaning",
+		},
+		"reverse": {
+			Direction: DirectionReverse,
+			Ask:       "question translate",
+			Answer:    "question word",
+			Option:    "answer word",
+		},
+		"state saved without direction": {
+			Direction: "",
+			Ask:       "question word",
+			Answer:    "question translate",
+			Option:    "answer translate; answer meaning",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			state := State{
+				Question:  question,
+				Answers:   []Row{option, question},
+				Direction: tc.Direction,
+			}
+
+			assert.Equal(t, tc.Ask, state.Ask())
+			assert.Equal(t, tc.Answer, state.AnswerOf(state.Question))
+			assert.Equal(t, tc.Option, state.AnswerOf(state.Answers[0]))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package golearn
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultAnswers is count of answers in picking mode if user hasn't chosen it.
+const DefaultAnswers = 4
+
+// distractorsPool is how many times more words are sampled than needed,
+// so there are enough of them after words with repeated translation are skipped.
+const distractorsPool = 3
+
+// Sampler returns up to limit random words other than the question. Words of category
+// of the question are returned if sameCategory is true, otherwise words of other categories.
+type Sampler func(sameCategory bool, limit int) ([]Row, error)
+
+// PickDistractors returns up to n wrong answers to question q.
+// Words of the same category go first, words of other categories are sampled
+// only if there aren't enough of them. Words with translation of q or of already
+// picked word are skipped, so the right answer is the only one.
+// Words of similar length are preferred, so length doesn't give the right answer away.
+func PickDistractors(q Row, n int, sample Sampler) ([]Row, error) {
+	var picked []Row
+	if n <= 0 {
+		return picked, nil
+	}
+
+	seen := map[string]bool{}
+	for _, t := range q.TranslateVariants() {
+		seen[normalizeTranslate(t)] = true
+	}
+
+	for _, sameCategory := range []bool{true, false} {
+		candidates, err := sample(sameCategory, n*distractorsPool)
+		if err != nil {
+			return picked, err
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return lengthDistance(q, candidates[i]) < lengthDistance(q, candidates[j])
+		})
+
+		for _, c := range candidates {
+			if len(picked) == n {
+				return picked, nil
+			}
+
+			if c.Word == q.Word || translatesAs(c, seen) {
+				continue
+			}
+
+			for _, t := range c.TranslateVariants() {
+				seen[normalizeTranslate(t)] = true
+			}
+			picked = append(picked, c)
+		}
+
+		if len(picked) == n {
+			return picked, nil
+		}
+	}
+
+	return picked, nil
+}
+
+// translatesAs reports if any translation of the row is already seen.
+func translatesAs(r Row, seen map[string]bool) bool {
+	for _, t := range r.TranslateVariants() {
+		if seen[normalizeTranslate(t)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeTranslate(t string) string {
+	return strings.ToLower(strings.TrimSpace(t))
+}
+
+// lengthDistance returns how much word and translation of the row differ in length from ones of q.
+func lengthDistance(q Row, r Row) int {
+	return absInt(utf8.RuneCountInString(q.Word)-utf8.RuneCountInString(r.Word)) +
+		absInt(utf8.RuneCountInString(q.Translate)-utf8.RuneCountInString(r.Translate))
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,109 @@
+package golearn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickDistractors(t *testing.T) {
+	q := Row{Word: "가다", Translate: "идти", Category: "verbs"}
+
+	testCases := map[string]struct {
+		N        int
+		Same     []Row
+		Other    []Row
+		Expected []Row
+	}{
+		"same category": {
+			N: 2,
+			Same: []Row{
+				{Word: "오다", Translate: "приходить", Category: "verbs"},
+				{Word: "보다", Translate: "видеть", Category: "verbs"},
+			},
+			Other: []Row{
+				{Word: "집", Translate: "дом", Category: "nouns"},
+			},
+			Expected: []Row{
+				{Word: "보다", Translate: "видеть", Category: "verbs"},
+				{Word: "오다", Translate: "приходить", Category: "verbs"},
+			},
+		},
+		"fallback to other categories": {
+			N: 3,
+			Same: []Row{
+				{Word: "오다", Translate: "приходить", Category: "verbs"},
+			},
+			Other: []Row{
+				{Word: "집", Translate: "дом", Category: "nouns"},
+				{Word: "물", Translate: "вода", Category: "nouns"},
+			},
+			Expected: []Row{
+				{Word: "오다", Translate: "приходить", Category: "verbs"},
+				{Word: "물", Translate: "вода", Category: "nouns"},
+				{Word: "집", Translate: "дом", Category: "nouns"},
+			},
+		},
+		"repeated translations": {
+			N: 3,
+			Same: []Row{
+				{Word: "걷다", Translate: "Идти", Category: "verbs"},
+				{Word: "오다", Translate: "приходить", Category: "verbs"},
+				{Word: "방문하다", Translate: "навещать", Translations: []string{"навещать", "приходить"}, Category: "verbs"},
+			},
+			Other: []Row{
+				{Word: "가다", Translate: "ехать", Category: "travel"},
+				{Word: "집", Translate: "дом", Category: "nouns"},
+			},
+			Expected: []Row{
+				{Word: "오다", Translate: "приходить", Category: "verbs"},
+				{Word: "집", Translate: "дом", Category: "nouns"},
+			},
+		},
+		"similar length first": {
+			N: 1,
+			Same: []Row{
+				{Word: "공부하다", Translate: "заниматься учёбой", Category: "verbs"},
+				{Word: "자다", Translate: "спать", Category: "verbs"},
+			},
+			Expected: []Row{
+				{Word: "자다", Translate: "спать", Category: "verbs"},
+			},
+		},
+		"no answers": {
+			N: 0,
+			Same: []Row{
+				{Word: "오다", Translate: "приходить", Category: "verbs"},
+			},
+			Expected: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			sample := func(sameCategory bool, limit int) ([]Row, error) {
+				assert.Equal(t, tc.N*distractorsPool, limit)
+				if sameCategory {
+					return append([]Row{}, tc.Same...), nil
+				}
+				return append([]Row{}, tc.Other...), nil
+			}
+
+			distractors, err := PickDistractors(q, tc.N, sample)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Expected, distractors)
+		})
+	}
+}
+
+func TestPickDistractorsWithSampleError(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	_, err := PickDistractors(Row{Word: "가다", Translate: "идти"}, 3, func(sameCategory bool, limit int) ([]Row, error) {
+		return nil, sampleError
+	})
+
+	assert.Equal(t, sampleError, err)
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/sergeiten/golearn"
+)
+
+// addWord starts conversation of adding word to user own collection.
+func (e *Engine) addWord(req *request) (message string, markup Keyboard, err error) {
+	err = e.db.SetState(req.ctx, golearn.State{
+		UserKey:   req.update.UserID,
+		Step:      golearn.StepWord,
+		Timestamp: req.now().Unix(),
+	})
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	return req.lang["add_word_word"], e.addWordKeyboard(req.lang), nil
+}
+
+// addWordStep saves user message as the next part of the word being added:
+// the word itself, then its translation and then name of collection.
+// Word is inserted once collection is known and conversation continues with the next word.
+func (e *Engine) addWordStep(req *request, state golearn.State) (message string, markup Keyboard, err error) {
+	text := strings.TrimSpace(req.update.Message)
+	keyboard := e.addWordKeyboard(req.lang)
+
+	next := golearn.State{
+		UserKey:   req.update.UserID,
+		Question:  state.Question,
+		Timestamp: req.now().Unix(),
+	}
+
+	// word and translation are asked again, state isn't changed
+	if (state.Step == golearn.StepWord || state.Step == golearn.StepTranslate) && len(golearn.ParseVariants(text)) == 0 {
+		return req.lang["add_word_empty"], keyboard, nil
+	}
+
+	// collection is asked again too, word without collection isn't inserted
+	if state.Step == golearn.StepCollection && text == "" {
+		return req.lang["add_word_empty"], keyboard, nil
+	}
+
+	switch state.Step {
+	case golearn.StepWord:
+		next.Step = golearn.StepTranslate
+		next.Question.Word = text
+		message = fmt.Sprintf(req.lang["add_word_translate"], html.EscapeString(text))
+	case golearn.StepTranslate:
+		next.Step = golearn.StepCollection
+		next.Question.Translate = text
+		message = fmt.Sprintf(req.lang["add_word_collection"], html.EscapeString(state.Question.Word))
+
+		// offer collection of previous word
+		if state.Question.Category != "" {
+			keyboard.Rows = append(buttons([]string{state.Question.Category}).Rows, keyboard.Rows...)
+		}
+	case golearn.StepCollection:
+		row := golearn.NewRow(state.Question.Word, state.Question.Translate, text)
+		row.Owner = req.update.UserID
+
+		err = e.db.InsertWord(req.ctx, row)
+		if err != nil {
+			return "", Keyboard{}, err
+		}
+
+		next.Step = golearn.StepWord
+		next.Question = golearn.Row{
+			Category: text,
+		}
+		message = fmt.Sprintf(req.lang["word_added"], html.EscapeString(row.Word), html.EscapeString(text))
+	default:
+		return "", Keyboard{}, fmt.Errorf("unknown step %q of adding word", state.Step)
+	}
+
+	err = e.db.SetState(req.ctx, next)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	return message, keyboard, nil
+}
+
+// addWordDone finishes conversation of adding words by saving state without step.
+// Previous states aren't deleted, recently asked words are taken from them.
+func (e *Engine) addWordDone(req *request) (message string, markup Keyboard, err error) {
+	err = e.db.SetState(req.ctx, golearn.State{
+		UserKey:   req.update.UserID,
+		Timestamp: req.now().Unix(),
+	})
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	return req.lang["welcome"], e.mainMenuKeyboard(req.lang), nil
+}
+
+func (e *Engine) addWordKeyboard(lang golearn.Language) Keyboard {
+	return buttons(
+		[]string{
+			lang["add_word_done"],
+		},
+	)
+}
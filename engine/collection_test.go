@@ -0,0 +1,290 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddWord(t *testing.T) {
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	update := golearn.Update{
+		ChatID:  "177374215",
+		UserID:  "177374215",
+		Message: lang["add_word"],
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	dbService.On("SetState", mock.Anything, golearn.State{
+		UserKey:   update.UserID,
+		Step:      golearn.StepWord,
+		Timestamp: now().Unix(),
+	}).Return(nil)
+
+	message, markup, err := engine.addWord(newRequest(&update, golearn.User{}, now))
+
+	assert.Nil(t, err)
+	assert.Equal(t, lang["add_word_word"], message)
+	assert.Equal(t, buttons(
+		[]string{lang["add_word_done"]},
+	), markup)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestAddWordStep(t *testing.T) {
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	done := []string{lang["add_word_done"]}
+
+	testCases := map[string]struct {
+		Message     string
+		State       golearn.State
+		Row         *golearn.Row
+		InsertError error
+		NextState   *golearn.State
+		Reply       string
+		Markup      Keyboard
+		Error       error
+	}{
+		"word": {
+			Message: "가다; 가기",
+			State: golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepWord,
+			},
+			NextState: &golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepTranslate,
+				Question: golearn.Row{
+					Word: "가다; 가기",
+				},
+				Timestamp: now().Unix(),
+			},
+			Reply: "Отправьте перевод слова <b>가다; 가기</b>. Несколько переводов можно разделить точкой с запятой",
+			Markup: buttons(
+				done,
+			),
+		},
+		"translation offers previous collection": {
+			Message: "идти",
+			State: golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepTranslate,
+				Question: golearn.Row{
+					Word:     "가다",
+					Category: "<verbs>",
+				},
+			},
+			NextState: &golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepCollection,
+				Question: golearn.Row{
+					Word:      "가다",
+					Translate: "идти",
+					Category:  "<verbs>",
+				},
+				Timestamp: now().Unix(),
+			},
+			Reply: "Отправьте название коллекции для <b>가다</b>",
+			Markup: buttons(
+				[]string{"<verbs>"},
+				done,
+			),
+		},
+		"collection": {
+			Message: "<verbs>",
+			State: golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepCollection,
+				Question: golearn.Row{
+					Word:      "가다",
+					Translate: "идти; ходить",
+				},
+			},
+			Row: &golearn.Row{
+				Word:         "가다",
+				Translate:    "идти",
+				Translations: []string{"идти", "ходить"},
+				Category:     "<verbs>",
+				Owner:        "177374215",
+			},
+			NextState: &golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepWord,
+				Question: golearn.Row{
+					Category: "<verbs>",
+				},
+				Timestamp: now().Unix(),
+			},
+			Reply: "<b>가다</b> добавлено в коллекцию <b>&lt;verbs&gt;</b>. Отправьте следующее слово или нажмите Готово",
+			Markup: buttons(
+				done,
+			),
+		},
+		"empty word": {
+			Message: " ; ",
+			State: golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepWord,
+			},
+			Reply: lang["add_word_empty"],
+			Markup: buttons(
+				done,
+			),
+		},
+		"empty translation": {
+			Message: "  ",
+			State: golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepTranslate,
+				Question: golearn.Row{
+					Word: "가다",
+				},
+			},
+			Reply: lang["add_word_empty"],
+			Markup: buttons(
+				done,
+			),
+		},
+		"empty collection": {
+			Message: " \t ",
+			State: golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepCollection,
+				Question: golearn.Row{
+					Word:      "가다",
+					Translate: "идти",
+				},
+			},
+			Reply: lang["add_word_empty"],
+			Markup: buttons(
+				done,
+			),
+		},
+		"collection with insert error": {
+			Message: "verbs",
+			State: golearn.State{
+				UserKey: "177374215",
+				Step:    golearn.StepCollection,
+				Question: golearn.Row{
+					Word:      "가다",
+					Translate: "идти",
+				},
+			},
+			Row: &golearn.Row{
+				Word:      "가다",
+				Translate: "идти",
+				Category:  "verbs",
+				Owner:     "177374215",
+			},
+			InsertError: errors.New("sample error"),
+			Reply:       "",
+			Markup:      Keyboard{},
+			Error:       errors.New("sample error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+			user := golearn.User{
+				UserID: "177374215",
+				Mode:   golearn.ModePicking,
+			}
+
+			update := golearn.Update{
+				ChatID:  "177374215",
+				UserID:  "177374215",
+				Message: tc.Message,
+			}
+
+			dbService.On("GetState", mock.Anything, update.UserID).Return(tc.State, nil)
+			if tc.Row != nil {
+				dbService.On("InsertWord", mock.Anything, *tc.Row).Return(tc.InsertError)
+			}
+			if tc.NextState != nil {
+				dbService.On("SetState", mock.Anything, *tc.NextState).Return(nil)
+			}
+
+			// conversation is continued by messages which aren't commands
+			message, markup, err := engine.answer(newRequest(&update, user, now))
+
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Reply, message)
+			assert.Equal(t, tc.Markup, markup)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddWordDone(t *testing.T) {
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	update := golearn.Update{
+		ChatID:  "177374215",
+		UserID:  "177374215",
+		Message: lang["add_word_done"],
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	// states aren't reset, recently asked words are kept in them
+	dbService.On("SetState", mock.Anything, golearn.State{
+		UserKey:   update.UserID,
+		Timestamp: now().Unix(),
+	}).Return(nil)
+
+	message, markup, err := engine.addWordDone(newRequest(&update, golearn.User{}, now))
+
+	assert.Nil(t, err)
+	assert.Equal(t, lang["welcome"], message)
+	assert.Equal(t, engine.mainMenuKeyboard(lang), markup)
+
+	// messages after the conversation aren't taken as answers
+	dbService.On("GetState", mock.Anything, update.UserID).Return(golearn.State{
+		UserKey:   update.UserID,
+		Timestamp: now().Unix(),
+	}, nil)
+
+	update.Message = "가다"
+	message, markup, err = engine.answer(newRequest(&update, golearn.User{}, now))
+
+	assert.Nil(t, err)
+	assert.Equal(t, lang["welcome"], message)
+	assert.Equal(t, engine.mainMenuKeyboard(lang), markup)
+
+	dbService.AssertExpectations(t)
+}
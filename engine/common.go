@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"github.com/sergeiten/golearn"
+)
+
+// MainMenuKeyboard returns keyboard of main menu, it's shown to the user
+// who hasn't sent anything yet by messengers which support it.
+func (e *Engine) MainMenuKeyboard() Keyboard {
+	return e.mainMenuKeyboard(e.lang)
+}
+
+func (e *Engine) mainMenuKeyboard(lang golearn.Language) Keyboard {
+	return buttons(
+		[]string{
+			lang["start"],
+			lang["statistics"],
+		},
+		[]string{
+			lang["settings"],
+			lang["help"],
+		},
+	)
+}
+
+func (e *Engine) mainMenu(req *request) (message string, markup Keyboard, err error) {
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	return req.lang["welcome"], keyboard, nil
+}
+
+func (e *Engine) help(req *request) (message string, markup Keyboard, err error) {
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	return req.lang["help_message"], keyboard, nil
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainMenuKeyboard(t *testing.T) {
+	engine = New(Config{
+		DBService: nil,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	expectedMarkup := buttons(
+		[]string{
+			lang["start"],
+			lang["statistics"],
+		},
+		[]string{
+			lang["settings"],
+			lang["help"],
+		},
+	)
+
+	markup := engine.mainMenuKeyboard(lang)
+
+	assert.Equal(t, expectedMarkup, markup)
+}
+
+func TestMainMenu(t *testing.T) {
+	engine = New(Config{
+		DBService: nil,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	expectedMessage := lang["welcome"]
+	expectedMarkup := buttons(
+		[]string{
+			lang["start"],
+			lang["statistics"],
+		},
+		[]string{
+			lang["settings"],
+			lang["help"],
+		},
+	)
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	message, markup, err := engine.mainMenu(newRequest(&update, golearn.User{}, time.Now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, nil, err)
+}
+
+func TestHelp(t *testing.T) {
+	engine = New(Config{
+		DBService: nil,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	expectedMessage := lang["help_message"]
+	expectedMarkup := buttons(
+		[]string{
+			lang["start"],
+			lang["statistics"],
+		},
+		[]string{
+			lang["settings"],
+			lang["help"],
+		},
+	)
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	message, markup, err := engine.help(newRequest(&update, golearn.User{}, time.Now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, nil, err)
+}
@@ -0,0 +1,233 @@
+// Package engine implements commands and quiz of the bot independent of messenger.
+// Messenger handlers pass updates to Engine and translate its replies to own format.
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/sergeiten/golearn"
+)
+
+// Button is button shown to the user. Data is sent back instead of text
+// by messengers which support it, e.g. by inline buttons of Telegram.
+type Button struct {
+	Text string
+	Data string
+}
+
+// Keyboard is set of buttons shown with reply placed by rows.
+type Keyboard struct {
+	Rows [][]Button
+	// Inline keyboard belongs to the message, e.g. answer options,
+	// otherwise it replaces keyboard of the user
+	Inline bool
+}
+
+// Reply is messenger independent reply to the user.
+// Text may contain <b> and <i> tags of HTML and escaped user input.
+type Reply struct {
+	Text     string
+	Keyboard Keyboard
+}
+
+// Engine handles updates of users.
+type Engine struct {
+	db      golearn.DBService
+	lang    golearn.Language
+	cols    int
+	matcher golearn.Matcher
+	recent  int
+	// maxButtons limits count of buttons of long menus, zero is no limit
+	maxButtons int
+	perm       func(n int) []int
+	coin       func() bool
+	newID      func() string
+	now        func() time.Time
+}
+
+// Config config for making Engine instance.
+type Config struct {
+	DBService golearn.DBService
+	Lang      golearn.Language
+	// ColsCount is number of buttons in a row of options
+	ColsCount int
+	Matcher   golearn.Matcher
+	// RecentWords is count of recently asked words which aren't asked again, zero disables it
+	RecentWords int
+	// MaxButtons limits count of buttons of messenger, longer menus are paged, zero is no limit
+	MaxButtons int
+}
+
+// request represents context of single update: the update itself, its user,
+// language of replies and clock. It's passed to every command function.
+// ctx is context of the update, it's passed to every database call.
+type request struct {
+	ctx    context.Context
+	update *golearn.Update
+	user   golearn.User
+	lang   golearn.Language
+	now    func() time.Time
+}
+
+// New returns Engine instance.
+func New(cfg Config) *Engine {
+	matcher := cfg.Matcher
+	if matcher == nil {
+		matcher = golearn.NewMatcher(golearn.DefaultMaxDistance)
+	}
+
+	// keyboards are built by rows of cols buttons, so at least one is required
+	cols := cfg.ColsCount
+	if cols < 1 {
+		cols = 1
+	}
+
+	return &Engine{
+		db:         cfg.DBService,
+		lang:       cfg.Lang,
+		cols:       cols,
+		matcher:    matcher,
+		recent:     cfg.RecentWords,
+		maxButtons: cfg.MaxButtons,
+		perm:       rand.Perm,
+		coin: func() bool {
+			return rand.Intn(2) == 0
+		},
+		newID: golearn.NewStateID,
+		now:   time.Now,
+	}
+}
+
+// Handle registers user of the update if it's new one and returns reply to the update.
+// Updates are handled concurrently, so everything related to the update
+// is kept in request and engine itself isn't changed.
+func (e *Engine) Handle(ctx context.Context, update *golearn.Update) (Reply, error) {
+	user, err := e.getOrCreateUser(ctx, update)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	req := &request{
+		ctx:    ctx,
+		update: update,
+		user:   user,
+		lang:   e.lang,
+		now:    e.now,
+	}
+
+	message, keyboard, err := e.handle(req)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	return Reply{
+		Text:     message,
+		Keyboard: keyboard,
+	}, nil
+}
+
+func (e *Engine) handle(req *request) (string, Keyboard, error) {
+	text := req.update.Message
+
+	switch {
+	case text == req.lang["main_menu"]:
+		return e.mainMenu(req)
+	case text == "/start":
+		return e.mainMenu(req)
+	case text == req.lang["help"]:
+		return e.help(req)
+	case text == req.lang["start"]:
+		return e.start(req)
+	case text == req.lang["next_word"]:
+		return e.start(req)
+	case text == req.lang["again"]:
+		return e.again(req)
+	case text == req.lang["settings"]:
+		return e.settings(req)
+	case text == req.lang["statistics"]:
+		return e.statistics(req)
+	case text == req.lang["mode_picking"]:
+		return e.setMode(req, golearn.ModePicking)
+	case text == req.lang["mode_typing"]:
+		return e.setMode(req, golearn.ModeTyping)
+	case text == req.lang["mode_leitner"]:
+		return e.setMode(req, golearn.ModeLeitner)
+	case text == req.lang["direction_forward"]:
+		return e.setDirection(req, golearn.DirectionForward)
+	case text == req.lang["direction_reverse"]:
+		return e.setDirection(req, golearn.DirectionReverse)
+	case text == req.lang["direction_mixed"]:
+		return e.setDirection(req, golearn.DirectionMixed)
+	case text == req.lang["latin_input_on"]:
+		return e.setLatinInput(req, true)
+	case text == req.lang["latin_input_off"]:
+		return e.setLatinInput(req, false)
+	case text == req.lang["answers"]:
+		return e.answers(req)
+	case text == req.lang["answers_3"]:
+		return e.setAnswers(req, 3)
+	case text == req.lang["answers_4"]:
+		return e.setAnswers(req, 4)
+	case text == req.lang["answers_6"]:
+		return e.setAnswers(req, 6)
+	case text == req.lang["add_word"]:
+		return e.addWord(req)
+	case text == req.lang["add_word_done"]:
+		return e.addWordDone(req)
+	case text == req.lang["show_answer"]:
+		return e.showAnswer(req)
+	case text == req.lang["categories"]:
+		return e.categories(req)
+	case strings.HasPrefix(text, req.lang["categories_icon"]):
+		return e.setCategory(req)
+	case text == req.lang["reset_category"]:
+		return e.resetCategory(req)
+	case morePage(req.lang, text) > 0:
+		return e.categoriesPage(req, morePage(req.lang, text))
+	case req.update.CallbackID != "" && strings.HasPrefix(text, answerDataPrefix):
+		return e.answerCallback(req)
+	default:
+		return e.answer(req)
+	}
+}
+
+func (e *Engine) getOrCreateUser(ctx context.Context, update *golearn.Update) (golearn.User, error) {
+	u := golearn.User{
+		UserID:   update.UserID,
+		Username: update.Username,
+		Name:     update.Name,
+		Mode:     golearn.ModePicking,
+	}
+
+	exist, err := e.db.ExistUser(ctx, u)
+	if err != nil {
+		return u, err
+	}
+
+	if exist {
+		return e.db.GetUser(ctx, u.UserID)
+	}
+
+	return u, e.db.InsertUser(ctx, u)
+}
+
+// buttons returns keyboard of passed rows of button texts.
+func buttons(rows ...[]string) Keyboard {
+	keyboard := Keyboard{
+		Rows: make([][]Button, len(rows)),
+	}
+
+	for i, row := range rows {
+		keyboard.Rows[i] = make([]Button, len(row))
+		for j, text := range row {
+			keyboard.Rows[i][j] = Button{
+				Text: text,
+			}
+		}
+	}
+
+	return keyboard
+}
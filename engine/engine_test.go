@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var engine *Engine
+var lang golearn.Language
+
+func init() {
+	langFilename := fmt.Sprintf("../lang.%s.json", "ru")
+	langContent, err := ioutil.ReadFile(langFilename)
+	golearn.LogFatal(err, "failed to get language file content")
+
+	lang, err = golearn.GetLanguage(langContent)
+	golearn.LogFatal(err, "failed to create language instance")
+}
+
+// newRequest returns request of passed update and user with test language.
+func newRequest(update *golearn.Update, user golearn.User, now func() time.Time) *request {
+	return &request{
+		ctx:    context.Background(),
+		update: update,
+		user:   user,
+		lang:   lang,
+		now:    now,
+	}
+}
+
+func TestGetOrCreateUser(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	testCases := map[string]struct {
+		User        golearn.User
+		Update      golearn.Update
+		ReturnExist bool
+		ReturnError error
+	}{
+		"user exists with no error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Update: golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "command",
+			},
+			ReturnExist: true,
+			ReturnError: nil,
+		},
+		"user exists with error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Update: golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "command",
+			},
+			ReturnExist: false,
+			ReturnError: sampleError,
+		},
+		"user no exists with no error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Update: golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "command",
+			},
+			ReturnExist: false,
+			ReturnError: nil,
+		},
+		"user no exists with error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Update: golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "command",
+			},
+			ReturnExist: false,
+			ReturnError: sampleError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("ExistUser", mock.Anything, tc.User).Return(tc.ReturnExist, tc.ReturnError)
+
+			if tc.ReturnError == nil {
+				if tc.ReturnExist {
+					dbService.On("GetUser", mock.Anything, tc.User.UserID).Return(tc.User, nil)
+				} else {
+					dbService.On("InsertUser", mock.Anything, tc.User).Return(nil)
+				}
+			}
+
+			user, err := engine.getOrCreateUser(context.Background(), &tc.Update)
+
+			assert.Equal(t, tc.User, user)
+
+			assert.Equal(t, tc.ReturnError, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
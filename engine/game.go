@@ -0,0 +1,397 @@
+package engine
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/sergeiten/golearn"
+)
+
+func (e *Engine) start(req *request) (message string, markup Keyboard, err error) {
+	switch req.user.Mode {
+	case golearn.ModePicking, golearn.ModeLeitner:
+		return e.startWithPickingMode(req)
+	case golearn.ModeTyping:
+		return e.startWithTypingMode(req)
+	default:
+		return "", Keyboard{}, fmt.Errorf("failed to start, undefined mode for user: %v", req.user)
+	}
+}
+
+// nextQuestion returns the word to ask user, recently asked words are skipped if possible.
+func (e *Engine) nextQuestion(req *request, user golearn.User) (golearn.Row, error) {
+	var recent []string
+	if e.recent > 0 {
+		var err error
+		recent, err = e.db.RecentWords(req.ctx, req.update.UserID, e.recent)
+		if err != nil {
+			return golearn.Row{}, err
+		}
+	}
+
+	return e.db.NextDueQuestion(req.ctx, req.update.UserID, user.Category, req.now(), recent)
+}
+
+func (e *Engine) startWithPickingMode(req *request) (message string, markup Keyboard, err error) {
+	user, err := e.db.GetUser(req.ctx, req.update.UserID)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	question, err := e.nextQuestion(req, user)
+	if err == golearn.ErrNoWords {
+		return req.lang["no_words"], Keyboard{}, nil
+	}
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	answers, err := e.db.RandomAnswers(req.ctx, req.update.UserID, question, user.AnswersCount())
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	if len(answers) == 0 {
+		return req.lang["no_words"], Keyboard{}, nil
+	}
+
+	// shuffle answers
+	shuffledAnswers := make([]golearn.Row, len(answers))
+	perm := e.perm(len(answers))
+	for i, v := range perm {
+		shuffledAnswers[v] = answers[i]
+	}
+
+	// save state
+	s := golearn.State{
+		ID:        e.newID(),
+		UserKey:   req.update.UserID,
+		Question:  question,
+		Answers:   shuffledAnswers,
+		Direction: golearn.QuestionDirection(user, e.coin),
+		Timestamp: req.now().Unix(),
+	}
+
+	err = e.db.SetState(req.ctx, s)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := e.answersKeyboard(req.lang, s)
+
+	return html.EscapeString(s.Ask()), keyboard, nil
+}
+
+func (e *Engine) startWithTypingMode(req *request) (message string, markup Keyboard, err error) {
+	user, err := e.db.GetUser(req.ctx, req.update.UserID)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	question, err := e.nextQuestion(req, user)
+	if err == golearn.ErrNoWords {
+		return req.lang["no_words"], Keyboard{}, nil
+	}
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	// save state
+	s := golearn.State{
+		UserKey:   req.update.UserID,
+		Question:  question,
+		Answers:   []golearn.Row{},
+		Direction: golearn.QuestionDirection(user, e.coin),
+		Timestamp: req.now().Unix(),
+	}
+
+	err = e.db.SetState(req.ctx, s)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := buttons(
+		[]string{
+			req.lang["next_word"],
+			req.lang["show_answer"],
+			req.lang["main_menu"],
+		},
+	)
+
+	return html.EscapeString(s.Ask()), keyboard, nil
+}
+
+func (e *Engine) answer(req *request) (message string, markup Keyboard, err error) {
+	state, err := e.getState(req)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	// user is adding word to own collection
+	if state.Step != "" {
+		return e.addWordStep(req, state)
+	}
+
+	// nothing is asked, e.g. user has finished adding words
+	if state.Question.Word == "" {
+		return req.lang["welcome"], e.mainMenuKeyboard(req.lang), nil
+	}
+
+	return e.answerState(req, state)
+}
+
+// answerCallback checks answer picked by inline button. Callback data refers to option
+// of the question by its index, so button text doesn't have to be matched.
+func (e *Engine) answerCallback(req *request) (message string, markup Keyboard, err error) {
+	stateID, index, err := parseAnswerData(req.update.Message)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	state, err := e.getState(req)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	// buttons of previous questions stay in chat history
+	if state.ID != stateID || index >= len(state.Answers) {
+		return req.lang["question_expired"], e.mainMenuKeyboard(req.lang), nil
+	}
+
+	update := *req.update
+	update.Message = state.AnswerOf(state.Answers[index])
+
+	picked := *req
+	picked.update = &update
+
+	return e.answerState(&picked, state)
+}
+
+// answerState checks user answer to the question of passed state.
+// The question is answered only once, repeated answers aren't graded again.
+func (e *Engine) answerState(req *request, state golearn.State) (message string, markup Keyboard, err error) {
+	if state.Answered {
+		return req.lang["question_expired"], e.mainMenuKeyboard(req.lang), nil
+	}
+
+	typed := e.typedAnswer(req, state, req.update.Message)
+	verdict := e.checkAnswer(req, state, typed)
+	// mistakes are shown against accepted variant user has tried to type
+	expected := golearn.ClosestVariant(state.VariantsOf(state.Question), typed)
+	isRight := verdict != golearn.VerdictWrong
+
+	activity := golearn.Activity{
+		UserID:     req.update.UserID,
+		State:      state,
+		Answer:     req.update.Message,
+		IsRight:    isRight,
+		Verdict:    verdict,
+		Similarity: golearn.JamoSimilarity(expected, typed),
+		Timestamp:  req.now(),
+	}
+
+	// save activity
+	err = e.db.InsertActivity(req.ctx, activity)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	// reschedule next review of the word
+	review, err := e.db.GetReview(req.ctx, req.update.UserID, state.Question)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	if req.user.Mode == golearn.ModeLeitner {
+		review = review.Leitner(activity.IsRight, activity.Timestamp)
+	} else {
+		review = review.Schedule(activity.Quality(), activity.Timestamp)
+	}
+
+	err = e.db.SetReview(req.ctx, review)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	state.Answered = true
+	state.Timestamp = req.now().Unix()
+
+	err = e.db.SetState(req.ctx, state)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	rows := [][]string{
+		{req.lang["next_word"]},
+	}
+	message = req.lang["right"]
+	if verdict == golearn.VerdictClose {
+		message = fmt.Sprintf(req.lang["almost"], golearn.HighlightDiff(expected, typed))
+	}
+	if !isRight {
+		message = req.lang["wrong"]
+
+		if req.user.Mode == golearn.ModePicking || req.user.Mode == golearn.ModeLeitner {
+			rows = append(rows, []string{req.lang["again"]})
+		}
+
+		if req.user.Mode == golearn.ModeTyping {
+			message += "\n\n" + fmt.Sprintf(req.lang["right_answer_is"], golearn.HighlightDiff(expected, typed))
+		}
+	}
+
+	return message, buttons(rows...), nil
+}
+
+func (e *Engine) showAnswer(req *request) (message string, markup Keyboard, err error) {
+	state, err := e.getState(req)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := buttons(
+		[]string{
+			req.lang["next_word"],
+			req.lang["main_menu"],
+		},
+	)
+
+	message = fmt.Sprintf(req.lang["right_answer_is"], html.EscapeString(state.AnswerOf(state.Question)))
+
+	return message, keyboard, nil
+}
+
+// checkAnswer returns verdict of user answer.
+// Typed answers are checked by tolerant matcher, answers picked from keyboard have to be exact.
+// Any accepted variant is right, as well as all of them joined as on the keyboard button.
+func (e *Engine) checkAnswer(req *request, state golearn.State, answer string) golearn.Verdict {
+	var matcher golearn.Matcher = golearn.ExactMatcher{}
+	if req.user.Mode == golearn.ModeTyping {
+		matcher = e.matcher
+	}
+
+	variants := []string{state.AnswerOf(state.Question)}
+	variants = append(variants, state.VariantsOf(state.Question)...)
+
+	return golearn.MatchAny(matcher, variants, answer)
+}
+
+// typedAnswer returns answer converted to Hangul if user has typed Korean word on Latin keyboard.
+// Answer is read both as Dubeolsik keystrokes and as Revised Romanization, the closest one
+// to the expected answer wins. Answer is returned as is if conversion is disabled by user.
+func (e *Engine) typedAnswer(req *request, state golearn.State, answer string) string {
+	expected := state.AnswerOf(state.Question)
+
+	if req.user.Mode != golearn.ModeTyping || !req.user.LatinInput || !golearn.HasHangul(expected) || golearn.HasHangul(answer) {
+		return answer
+	}
+
+	variants := state.VariantsOf(state.Question)
+	similarity := func(s string) float64 {
+		return golearn.JamoSimilarity(golearn.ClosestVariant(variants, s), s)
+	}
+
+	best := golearn.FromDubeolsik(answer)
+	romanized := golearn.FromRomanization(answer)
+	if similarity(romanized) > similarity(best) {
+		best = romanized
+	}
+
+	return best
+}
+
+// again asks the question once more, buttons of the answered question expire.
+func (e *Engine) again(req *request) (message string, markup Keyboard, err error) {
+	state, err := e.getState(req)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	if state.Answered {
+		state.ID = e.newID()
+		state.Answered = false
+		state.Timestamp = req.now().Unix()
+
+		err = e.db.SetState(req.ctx, state)
+		if err != nil {
+			return "", Keyboard{}, err
+		}
+	}
+
+	keyboard := e.answersKeyboard(req.lang, state)
+
+	return html.EscapeString(state.Ask()), keyboard, nil
+}
+
+// getState returns latest user state.
+// States saved before direction became configurable get default direction of user mode.
+func (e *Engine) getState(req *request) (golearn.State, error) {
+	state, err := e.db.GetState(req.ctx, req.update.UserID)
+	if err != nil {
+		return state, err
+	}
+
+	if state.Direction == "" {
+		state.Direction = golearn.DefaultDirection(req.user.Mode)
+	}
+
+	return state, nil
+}
+
+// answersKeyboard returns inline keyboard with answer options of passed state
+// placed by e.cols buttons in a row and main menu button in the last row.
+func (e *Engine) answersKeyboard(lang golearn.Language, state golearn.State) Keyboard {
+	var keyboard [][]Button
+	var row []Button
+
+	for i, a := range state.Answers {
+		row = append(row, Button{
+			Text: state.AnswerOf(a),
+			Data: answerData(state.ID, i),
+		})
+
+		if len(row) == e.cols || i == len(state.Answers)-1 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+
+	// data of command button is the command itself, so it's handled as typed one
+	keyboard = append(keyboard, []Button{
+		{
+			Text: lang["main_menu"],
+			Data: lang["main_menu"],
+		},
+	})
+
+	return Keyboard{
+		Rows:   keyboard,
+		Inline: true,
+	}
+}
+
+// answerDataPrefix starts callback data of answer buttons.
+const answerDataPrefix = "answer:"
+
+// answerData returns callback data of answer button: prefix, state id and index of option.
+// Telegram limits callback data by 64 bytes, so the option itself isn't included.
+func answerData(stateID string, index int) string {
+	return answerDataPrefix + stateID + ":" + strconv.Itoa(index)
+}
+
+// parseAnswerData returns state id and index of option from callback data of answer button.
+func parseAnswerData(data string) (string, int, error) {
+	parts := strings.Split(strings.TrimPrefix(data, answerDataPrefix), ":")
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid answer callback data: %s", data)
+	}
+
+	index, err := strconv.Atoi(parts[1])
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("invalid answer callback data: %s", data)
+	}
+
+	return parts[0], index, nil
+}
@@ -0,0 +1,1754 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/memory"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAnswersKeyboard(t *testing.T) {
+	engine = New(Config{
+		DBService: nil,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	words := []golearn.Row{
+		{
+			Word:      "test0",
+			Translate: "test0",
+		}, {
+			Word:      "test1",
+			Translate: "test1",
+		},
+		{
+			Word:      "test2",
+			Translate: "test2",
+		}, {
+			Word:      "test3",
+			Translate: "test3",
+		}, {
+			Word:      "test4",
+			Translate: "test4",
+		},
+	}
+
+	menu := []Button{
+		{Text: lang["main_menu"], Data: lang["main_menu"]},
+	}
+
+	testCases := map[string]struct {
+		Answers  []golearn.Row
+		Expected [][]Button
+	}{
+		"full rows": {
+			Answers: words[:4],
+			Expected: [][]Button{
+				{{Text: "test0", Data: "answer:5c6e5a1b:0"}, {Text: "test1", Data: "answer:5c6e5a1b:1"}},
+				{{Text: "test2", Data: "answer:5c6e5a1b:2"}, {Text: "test3", Data: "answer:5c6e5a1b:3"}},
+				menu,
+			},
+		},
+		"last row is not full": {
+			Answers: words,
+			Expected: [][]Button{
+				{{Text: "test0", Data: "answer:5c6e5a1b:0"}, {Text: "test1", Data: "answer:5c6e5a1b:1"}},
+				{{Text: "test2", Data: "answer:5c6e5a1b:2"}, {Text: "test3", Data: "answer:5c6e5a1b:3"}},
+				{{Text: "test4", Data: "answer:5c6e5a1b:4"}},
+				menu,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keyboard := engine.answersKeyboard(lang, golearn.State{
+				ID:        "5c6e5a1b",
+				Answers:   tc.Answers,
+				Direction: golearn.DirectionForward,
+			})
+
+			assert.Equal(t, Keyboard{
+				Rows:   tc.Expected,
+				Inline: true,
+			}, keyboard)
+		})
+	}
+}
+
+func TestParseAnswerData(t *testing.T) {
+	testCases := map[string]struct {
+		Data    string
+		StateID string
+		Index   int
+		IsError bool
+	}{
+		"valid data": {
+			Data:    answerData("5c6e5a1b", 3),
+			StateID: "5c6e5a1b",
+			Index:   3,
+		},
+		"state saved without id": {
+			Data:    answerData("", 1),
+			StateID: "",
+			Index:   1,
+		},
+		"index is not a number": {
+			Data:    "answer:5c6e5a1b:x",
+			IsError: true,
+		},
+		"negative index": {
+			Data:    "answer:5c6e5a1b:-1",
+			IsError: true,
+		},
+		"missing index": {
+			Data:    "answer:5c6e5a1b",
+			IsError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stateID, index, err := parseAnswerData(tc.Data)
+
+			if tc.IsError {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.StateID, stateID)
+			assert.Equal(t, tc.Index, index)
+		})
+	}
+}
+
+func TestAgain(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	state := golearn.State{
+		ID:      "5c6e5a1b",
+		UserKey: "177374215",
+		Question: golearn.Row{
+			Word:      "question word",
+			Translate: "question translate",
+		},
+		Answers: []golearn.Row{
+			{
+				Word:      "answer word 1",
+				Translate: "answer translate 1",
+			},
+			{
+				Word:      "answer word 2",
+				Translate: "answer translate 2",
+			},
+			{
+				Word:      "answer word 3",
+				Translate: "answer translate 3",
+			},
+			{
+				Word:      "answer word 4",
+				Translate: "answer translate 4",
+			},
+		},
+		Mode: golearn.ModePicking,
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	answered := state
+	answered.Answered = true
+
+	reopened := state
+	reopened.ID = "6d7f6b2c"
+	reopened.Timestamp = now().Unix()
+	// state is saved with default direction got by getState
+	reopened.Direction = golearn.DirectionForward
+
+	testCases := map[string]struct {
+		State golearn.State
+		// Reopened is saved if answered question is asked again
+		Reopened golearn.State
+		Error    error
+		Markup   Keyboard
+		Message  string
+	}{
+		"again after answer": {
+			State:    answered,
+			Reopened: reopened,
+			Error:    nil,
+			Markup: Keyboard{
+				Rows: [][]Button{
+					{
+						{Text: "answer translate 1", Data: "answer:6d7f6b2c:0"},
+						{Text: "answer translate 2", Data: "answer:6d7f6b2c:1"},
+					},
+					{
+						{Text: "answer translate 3", Data: "answer:6d7f6b2c:2"},
+						{Text: "answer translate 4", Data: "answer:6d7f6b2c:3"},
+					},
+					{
+						{Text: lang["main_menu"], Data: lang["main_menu"]},
+					},
+				},
+				Inline: true,
+			},
+			Message: "question word",
+		},
+		"again with no error": {
+			State: state,
+			Error: nil,
+			Markup: Keyboard{
+				Rows: [][]Button{
+					{
+						{Text: "answer translate 1", Data: "answer:5c6e5a1b:0"},
+						{Text: "answer translate 2", Data: "answer:5c6e5a1b:1"},
+					},
+					{
+						{Text: "answer translate 3", Data: "answer:5c6e5a1b:2"},
+						{Text: "answer translate 4", Data: "answer:5c6e5a1b:3"},
+					},
+					{
+						{Text: lang["main_menu"], Data: lang["main_menu"]},
+					},
+				},
+				Inline: true,
+			},
+			Message: "question word",
+		},
+		"again with html in word": {
+			State: func() golearn.State {
+				s := state
+				s.Answers = s.Answers[:1]
+				s.Question = golearn.Row{Word: "<b>a & b</b>", Translate: "answer translate 1"}
+				return s
+			}(),
+			Error: nil,
+			Markup: Keyboard{
+				Rows: [][]Button{
+					{
+						{Text: "answer translate 1", Data: "answer:5c6e5a1b:0"},
+					},
+					{
+						{Text: lang["main_menu"], Data: lang["main_menu"]},
+					},
+				},
+				Inline: true,
+			},
+			Message: "&lt;b&gt;a &amp; b&lt;/b&gt;",
+		},
+		"again with error": {
+			State:   state,
+			Error:   sampleError,
+			Markup:  Keyboard{},
+			Message: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			engine.newID = func() string {
+				return "6d7f6b2c"
+			}
+
+			dbService.On("GetState", mock.Anything, update.UserID).Return(tc.State, tc.Error)
+			if tc.Reopened.ID != "" {
+				dbService.On("SetState", mock.Anything, tc.Reopened).Return(nil)
+			}
+
+			message, markup, err := engine.again(newRequest(&update, golearn.User{}, now))
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAnswer(t *testing.T) {
+	engine = New(Config{
+		DBService: nil,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+	}
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	testCases := map[string]struct {
+		Message  string
+		Mode     string
+		Expected golearn.Verdict
+		State    golearn.State
+	}{
+		"typing mode right answer": {
+			Message:  "question word",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"typing mode wrong answer": {
+			Message:  "wrong",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictWrong,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"typing mode answer with different case and spacing": {
+			Message:  " Question  Word! ",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"typing mode answer with typo": {
+			Message:  "question wird",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictClose,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"typing mode answer in decomposed hangul": {
+			Message:  "\u1112\u1161\u11ab\u1100\u1173\u11af",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "한글",
+					Translate: "korean alphabet",
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"picking mode answer with typo": {
+			Message:  "question translata",
+			Mode:     golearn.ModePicking,
+			Expected: golearn.VerdictWrong,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionForward,
+			},
+		},
+		"picking mode right answer": {
+			Message:  "question translate",
+			Mode:     golearn.ModePicking,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionForward,
+			},
+		},
+		"typing mode forward direction right answer": {
+			Message:  "question translate",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionForward,
+			},
+		},
+		"picking mode reverse direction right answer": {
+			Message:  "question word",
+			Mode:     golearn.ModePicking,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"picking mode reverse direction wrong answer": {
+			Message:  "question translate",
+			Mode:     golearn.ModePicking,
+			Expected: golearn.VerdictWrong,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"typing mode any accepted translation": {
+			Message:  "ходить",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:         "가다",
+					Translate:    "идти",
+					Translations: []string{"идти", "ходить"},
+				},
+				Direction: golearn.DirectionForward,
+			},
+		},
+		"typing mode accepted spelling with typo": {
+			Message:  "자장먼",
+			Mode:     golearn.ModeTyping,
+			Expected: golearn.VerdictClose,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "짜장면",
+					Translate: "чачжанмён",
+					Spellings: []string{"짜장면", "자장면"},
+				},
+				Direction: golearn.DirectionReverse,
+			},
+		},
+		"picking mode button with all translations": {
+			Message:  "идти; ходить",
+			Mode:     golearn.ModePicking,
+			Expected: golearn.VerdictExact,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:         "가다",
+					Translate:    "идти",
+					Translations: []string{"идти", "ходить"},
+				},
+				Direction: golearn.DirectionForward,
+			},
+		},
+		"picking mode wrong answer": {
+			Message:  "wrong",
+			Mode:     golearn.ModePicking,
+			Expected: golearn.VerdictWrong,
+			State: golearn.State{
+				Question: golearn.Row{
+					Word:      "question word",
+					Translate: "question translate",
+				},
+				Direction: golearn.DirectionForward,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			update.Message = tc.Message
+			user.Mode = tc.Mode
+
+			verdict := engine.checkAnswer(newRequest(&update, user, time.Now), tc.State, update.Message)
+
+			assert.Equal(t, tc.Expected, verdict)
+		})
+	}
+}
+
+func TestTypedAnswer(t *testing.T) {
+	engine = New(Config{
+		DBService: nil,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	reverseState := golearn.State{
+		Question: golearn.Row{
+			Word:      "한글",
+			Translate: "корейский алфавит",
+		},
+		Direction: golearn.DirectionReverse,
+	}
+
+	forwardState := reverseState
+	forwardState.Direction = golearn.DirectionForward
+
+	testCases := map[string]struct {
+		Message    string
+		Mode       string
+		LatinInput bool
+		State      golearn.State
+		Expected   string
+	}{
+		"dubeolsik keystrokes": {
+			Message:    "gksrmf",
+			Mode:       golearn.ModeTyping,
+			LatinInput: true,
+			State:      reverseState,
+			Expected:   "한글",
+		},
+		"revised romanization": {
+			Message:    "hangeul",
+			Mode:       golearn.ModeTyping,
+			LatinInput: true,
+			State:      reverseState,
+			Expected:   "한글",
+		},
+		"answer typed in hangul": {
+			Message:    "한글",
+			Mode:       golearn.ModeTyping,
+			LatinInput: true,
+			State:      reverseState,
+			Expected:   "한글",
+		},
+		"latin input disabled": {
+			Message:    "gksrmf",
+			Mode:       golearn.ModeTyping,
+			LatinInput: false,
+			State:      reverseState,
+			Expected:   "gksrmf",
+		},
+		"expected answer isn't korean": {
+			Message:    "korejskij alfavit",
+			Mode:       golearn.ModeTyping,
+			LatinInput: true,
+			State:      forwardState,
+			Expected:   "korejskij alfavit",
+		},
+		"picking mode": {
+			Message:    "gksrmf",
+			Mode:       golearn.ModePicking,
+			LatinInput: true,
+			State:      reverseState,
+			Expected:   "gksrmf",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			user := golearn.User{
+				UserID:     "177374215",
+				Mode:       tc.Mode,
+				LatinInput: tc.LatinInput,
+			}
+			req := newRequest(&golearn.Update{UserID: user.UserID}, user, time.Now)
+
+			assert.Equal(t, tc.Expected, engine.typedAnswer(req, tc.State, tc.Message))
+		})
+	}
+}
+
+func TestShowAnswer(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	state := golearn.State{
+		UserKey: "177374215",
+		Question: golearn.Row{
+			Word:      "question word",
+			Translate: "question translate",
+		},
+		Answers: []golearn.Row{
+			{
+				Word:      "answer word 1",
+				Translate: "answer translate 1",
+			},
+			{
+				Word:      "answer word 2",
+				Translate: "answer translate 2",
+			},
+			{
+				Word:      "answer word 3",
+				Translate: "answer translate 3",
+			},
+			{
+				Word:      "answer word 4",
+				Translate: "answer translate 4",
+			},
+		},
+		Mode: golearn.ModePicking,
+	}
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	testCases := map[string]struct {
+		Question golearn.Row
+		Message  string
+		Markup   Keyboard
+		Error    error
+	}{
+		"with no error": {
+			Question: state.Question,
+			Message:  fmt.Sprintf(lang["right_answer_is"], state.Question.Word),
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+					lang["main_menu"],
+				},
+			),
+			Error: nil,
+		},
+		"with html in word": {
+			Question: golearn.Row{Word: "<b>a & b</b>", Translate: "question translate"},
+			Message:  fmt.Sprintf(lang["right_answer_is"], "&lt;b&gt;a &amp; b&lt;/b&gt;"),
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+					lang["main_menu"],
+				},
+			),
+			Error: nil,
+		},
+		"with error": {
+			Question: state.Question,
+			Message:  "",
+			Markup:   Keyboard{},
+			Error:    sampleError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			s := state
+			s.Question = tc.Question
+			dbService.On("GetState", mock.Anything, update.UserID).Return(s, tc.Error)
+
+			message, markup, err := engine.showAnswer(newRequest(&update, golearn.User{Mode: golearn.ModeTyping}, time.Now))
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAnswer(t *testing.T) {
+	state := golearn.State{
+		UserKey: "177374215",
+		Question: golearn.Row{
+			Word:      "question word",
+			Translate: "question translate",
+		},
+		Answers: []golearn.Row{
+			{
+				Word:      "answer word 1",
+				Translate: "answer translate 1",
+			},
+			{
+				Word:      "answer word 2",
+				Translate: "answer translate 2",
+			},
+			{
+				Word:      "answer word 3",
+				Translate: "answer translate 3",
+			},
+			{
+				Word:      "answer word 4",
+				Translate: "answer translate 4",
+			},
+		},
+		Mode: golearn.ModePicking,
+	}
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	// states saved without direction get default direction of user mode
+	forwardState := state
+	forwardState.Direction = golearn.DirectionForward
+
+	reverseState := state
+	reverseState.Direction = golearn.DirectionReverse
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		UpdateMessage string
+		Activity      golearn.Activity
+		Message       string
+		Markup        Keyboard
+		Error         error
+		Mode          string
+	}{
+		"with error": {
+			UpdateMessage: "message",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      state,
+				Answer:     "message",
+				IsRight:    true,
+				Verdict:    golearn.VerdictExact,
+				Similarity: golearn.JamoSimilarity(state.Question.Translate, "message"),
+				Timestamp:  now(),
+			},
+			Message: "",
+			Markup:  Keyboard{},
+			Error:   errors.New("sample error"),
+			Mode:    golearn.ModePicking,
+		},
+		"picking mode right answer": {
+			UpdateMessage: "question translate",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      forwardState,
+				Answer:     "question translate",
+				IsRight:    true,
+				Verdict:    golearn.VerdictExact,
+				Similarity: golearn.JamoSimilarity(state.Question.Translate, "question translate"),
+				Timestamp:  now(),
+			},
+			Message: lang["right"],
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+				},
+			),
+			Error: nil,
+			Mode:  golearn.ModePicking,
+		},
+		"picking mode wrong answer": {
+			UpdateMessage: "wrong",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      forwardState,
+				Answer:     "wrong",
+				IsRight:    false,
+				Verdict:    golearn.VerdictWrong,
+				Similarity: golearn.JamoSimilarity(state.Question.Translate, "wrong"),
+				Timestamp:  now(),
+			},
+			Message: lang["wrong"],
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+				},
+				[]string{
+					lang["again"],
+				},
+			),
+			Error: nil,
+			Mode:  golearn.ModePicking,
+		},
+		"leitner mode right answer": {
+			UpdateMessage: "question translate",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      forwardState,
+				Answer:     "question translate",
+				IsRight:    true,
+				Verdict:    golearn.VerdictExact,
+				Similarity: golearn.JamoSimilarity(state.Question.Translate, "question translate"),
+				Timestamp:  now(),
+			},
+			Message: lang["right"],
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+				},
+			),
+			Error: nil,
+			Mode:  golearn.ModeLeitner,
+		},
+		"leitner mode wrong answer": {
+			UpdateMessage: "wrong",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      forwardState,
+				Answer:     "wrong",
+				IsRight:    false,
+				Verdict:    golearn.VerdictWrong,
+				Similarity: golearn.JamoSimilarity(state.Question.Translate, "wrong"),
+				Timestamp:  now(),
+			},
+			Message: lang["wrong"],
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+				},
+				[]string{
+					lang["again"],
+				},
+			),
+			Error: nil,
+			Mode:  golearn.ModeLeitner,
+		},
+		"typing mode right answer": {
+			UpdateMessage: "question word",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      reverseState,
+				Answer:     "question word",
+				IsRight:    true,
+				Verdict:    golearn.VerdictExact,
+				Similarity: golearn.JamoSimilarity(state.Question.Word, "question word"),
+				Timestamp:  now(),
+			},
+			Message: lang["right"],
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+				},
+			),
+			Error: nil,
+			Mode:  golearn.ModeTyping,
+		},
+		"typing mode close answer": {
+			UpdateMessage: "question wird",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      reverseState,
+				Answer:     "question wird",
+				IsRight:    true,
+				Verdict:    golearn.VerdictClose,
+				Similarity: golearn.JamoSimilarity(state.Question.Word, "question wird"),
+				Timestamp:  now(),
+			},
+			Message: fmt.Sprintf(lang["almost"], "question w<b>o</b>rd"),
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+				},
+			),
+			Error: nil,
+			Mode:  golearn.ModeTyping,
+		},
+		"typing mode wrong answer": {
+			UpdateMessage: "wrong",
+			Activity: golearn.Activity{
+				UserID:     update.UserID,
+				State:      reverseState,
+				Answer:     "wrong",
+				IsRight:    false,
+				Verdict:    golearn.VerdictWrong,
+				Similarity: golearn.JamoSimilarity(state.Question.Word, "wrong"),
+				Timestamp:  now(),
+			},
+			Message: lang["wrong"] + "\n\n" + fmt.Sprintf(lang["right_answer_is"], "<b>questi</b>on<b> word</b>"),
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+				},
+			),
+			Error: nil,
+			Mode:  golearn.ModeTyping,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			update.Message = tc.UpdateMessage
+
+			dbService.On("GetState", mock.Anything, update.UserID).Return(state, tc.Error)
+
+			if tc.Error == nil {
+				dbService.On("InsertActivity", mock.Anything, tc.Activity).Return(nil)
+				review := golearn.NewReview(update.UserID, state.Question)
+				dbService.On("GetReview", mock.Anything, update.UserID, state.Question).Return(review, nil)
+				if tc.Mode == golearn.ModeLeitner {
+					dbService.On("SetReview", mock.Anything, review.Leitner(tc.Activity.IsRight, now())).Return(nil)
+				} else {
+					dbService.On("SetReview", mock.Anything, review.Schedule(tc.Activity.Quality(), now())).Return(nil)
+				}
+
+				answered := tc.Activity.State
+				answered.Answered = true
+				answered.Timestamp = now().Unix()
+				dbService.On("SetState", mock.Anything, answered).Return(nil)
+			}
+
+			message, markup, err := engine.answer(newRequest(&update, golearn.User{Mode: tc.Mode}, now))
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStartWithTypingMode(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModeTyping,
+		Category: "",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		Message       string
+		Markup        Keyboard
+		Error         error
+		Question      golearn.Row
+		RandomError   error
+		SetStateError error
+	}{
+		"with random question error": {
+			Message:       "",
+			Markup:        Keyboard{},
+			Error:         sampleError,
+			Question:      golearn.Row{},
+			RandomError:   sampleError,
+			SetStateError: nil,
+		},
+		"without words": {
+			Message:       lang["no_words"],
+			Markup:        Keyboard{},
+			Error:         nil,
+			Question:      golearn.Row{},
+			RandomError:   golearn.ErrNoWords,
+			SetStateError: nil,
+		},
+		"with set state error": {
+			Message:       "",
+			Markup:        Keyboard{},
+			Error:         sampleError,
+			Question:      golearn.Row{},
+			RandomError:   nil,
+			SetStateError: sampleError,
+		},
+		"no errors": {
+			Message: "question translate",
+			Markup: buttons(
+				[]string{
+					lang["next_word"],
+					lang["show_answer"],
+					lang["main_menu"],
+				},
+			),
+			Error: nil,
+			Question: golearn.Row{
+				Word:      "question word",
+				Translate: "question translate",
+			},
+			RandomError:   nil,
+			SetStateError: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("GetUser", mock.Anything, update.UserID).Return(user, nil)
+			dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), []string(nil)).Return(tc.Question, tc.RandomError)
+			if tc.RandomError == nil {
+				dbService.On("SetState", mock.Anything, golearn.State{
+					UserKey:   update.UserID,
+					Question:  tc.Question,
+					Answers:   []golearn.Row{},
+					Direction: golearn.DirectionReverse,
+					Timestamp: now().Unix(),
+				}).Return(tc.SetStateError)
+			}
+
+			message, markup, err := engine.startWithTypingMode(newRequest(&update, user, now))
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNextQuestion(t *testing.T) {
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+		Category: "verbs",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	question := golearn.Row{Word: "가다", Translate: "идти", Category: "verbs"}
+	sampleError := errors.New("sample error")
+
+	testCases := map[string]struct {
+		RecentWords int
+		Recent      []string
+		RecentError error
+		Question    golearn.Row
+		Error       error
+	}{
+		"recent words are disabled": {
+			RecentWords: 0,
+			Recent:      nil,
+			Question:    question,
+			Error:       nil,
+		},
+		"recent words are skipped": {
+			RecentWords: 5,
+			Recent:      []string{"오다", "보다"},
+			Question:    question,
+			Error:       nil,
+		},
+		"recent words with error": {
+			RecentWords: 5,
+			RecentError: sampleError,
+			Question:    golearn.Row{},
+			Error:       sampleError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService:   dbService,
+				Lang:        lang,
+				ColsCount:   2,
+				RecentWords: tc.RecentWords,
+			})
+
+			if tc.RecentWords > 0 {
+				dbService.On("RecentWords", mock.Anything, update.UserID, tc.RecentWords).Return(tc.Recent, tc.RecentError)
+			}
+			if tc.RecentError == nil {
+				dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), tc.Recent).Return(question, nil)
+			}
+
+			row, err := engine.nextQuestion(newRequest(&update, user, now), user)
+
+			assert.Equal(t, tc.Question, row)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStartWithPickingModeWithRandomQuestionError(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+		Category: "",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	expectedMessage := ""
+	expectedMarkup := Keyboard{}
+	expectedError := sampleError
+
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	dbService.On("GetUser", mock.Anything, update.UserID).Return(user, nil)
+	dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), []string(nil)).Return(golearn.Row{}, sampleError)
+
+	message, markup, err := engine.startWithPickingMode(newRequest(&update, user, now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, expectedError, err)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestStartWithPickingModeWithoutWords(t *testing.T) {
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+		Category: "empty",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	dbService.On("GetUser", mock.Anything, update.UserID).Return(user, nil)
+	dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), []string(nil)).Return(golearn.Row{}, golearn.ErrNoWords)
+
+	message, markup, err := engine.startWithPickingMode(newRequest(&update, user, now))
+
+	assert.Equal(t, lang["no_words"], message)
+	assert.Equal(t, Keyboard{}, markup)
+	assert.Nil(t, err)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestStartWithPickingModeWithRandomAnswersError(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+		Category: "",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	question := golearn.Row{
+		Word:      "question word",
+		Translate: "question translate",
+	}
+
+	expectedMessage := ""
+	expectedMarkup := Keyboard{}
+	expectedError := sampleError
+
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	dbService.On("GetUser", mock.Anything, update.UserID).Return(user, nil)
+	dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), []string(nil)).Return(question, nil)
+	dbService.On("RandomAnswers", mock.Anything, update.UserID, question, 4).Return([]golearn.Row{}, sampleError)
+
+	message, markup, err := engine.startWithPickingMode(newRequest(&update, user, now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, expectedError, err)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestStartWithPickingModeWithEmptyAnswersError(t *testing.T) {
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+		Category: "",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	question := golearn.Row{
+		Word:      "question word",
+		Translate: "question translate",
+	}
+
+	answers := []golearn.Row{}
+
+	expectedMessage := lang["no_words"]
+	expectedMarkup := Keyboard{}
+
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	dbService.On("GetUser", mock.Anything, update.UserID).Return(user, nil)
+	dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), []string(nil)).Return(question, nil)
+	dbService.On("RandomAnswers", mock.Anything, update.UserID, question, 4).Return(answers, nil)
+
+	message, markup, err := engine.startWithPickingMode(newRequest(&update, user, now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, nil, err)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestStartWithPickingModeWithSetStateError(t *testing.T) {
+	sampleError := errors.New("sample error")
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+		Category: "",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	question := golearn.Row{
+		Word:      "question word",
+		Translate: "question translate",
+	}
+
+	answers := []golearn.Row{
+		{
+			Word:      "answer word 1",
+			Translate: "answer translate 1",
+		},
+		{
+			Word:      "answer word 2",
+			Translate: "answer translate 2",
+		},
+		{
+			Word:      "answer word 3",
+			Translate: "answer translate 3",
+		},
+		{
+			Word:      "answer word 4",
+			Translate: "answer translate 4",
+		},
+	}
+
+	expectedMessage := ""
+	expectedMarkup := Keyboard{}
+
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	// keep answers order to know expected state
+	engine.perm = func(n int) []int {
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		return perm
+	}
+	engine.newID = func() string {
+		return "5c6e5a1b"
+	}
+
+	dbService.On("GetUser", mock.Anything, update.UserID).Return(user, nil)
+	dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), []string(nil)).Return(question, nil)
+	dbService.On("RandomAnswers", mock.Anything, update.UserID, question, 4).Return(answers, nil)
+	dbService.On("SetState", mock.Anything, golearn.State{
+		ID:        "5c6e5a1b",
+		UserKey:   update.UserID,
+		Question:  question,
+		Answers:   answers,
+		Direction: golearn.DirectionForward,
+		Timestamp: now().Unix(),
+	}).Return(sampleError)
+
+	message, markup, err := engine.startWithPickingMode(newRequest(&update, user, now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, sampleError, err)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestStartWithPickingMode(t *testing.T) {
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+		Category: "",
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	question := golearn.Row{
+		Word:      "question word",
+		Translate: "question translate",
+	}
+
+	answers := []golearn.Row{
+		{
+			Word:      "answer word",
+			Translate: "answer translate",
+		},
+		{
+			Word:      "answer word",
+			Translate: "answer translate",
+		},
+		{
+			Word:      "answer word",
+			Translate: "answer translate",
+		},
+		{
+			Word:      "answer word",
+			Translate: "answer translate",
+		},
+	}
+
+	expectedMessage := "question word"
+	expectedMarkup := Keyboard{
+		Rows: [][]Button{
+			{
+				{Text: "answer translate", Data: "answer:5c6e5a1b:0"},
+				{Text: "answer translate", Data: "answer:5c6e5a1b:1"},
+			},
+			{
+				{Text: "answer translate", Data: "answer:5c6e5a1b:2"},
+				{Text: "answer translate", Data: "answer:5c6e5a1b:3"},
+			},
+			{
+				{Text: lang["main_menu"], Data: lang["main_menu"]},
+			},
+		},
+		Inline: true,
+	}
+
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	// keep answers order to know expected state
+	engine.perm = func(n int) []int {
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		return perm
+	}
+	engine.newID = func() string {
+		return "5c6e5a1b"
+	}
+
+	dbService.On("GetUser", mock.Anything, update.UserID).Return(user, nil)
+	dbService.On("NextDueQuestion", mock.Anything, update.UserID, user.Category, now(), []string(nil)).Return(question, nil)
+	dbService.On("RandomAnswers", mock.Anything, update.UserID, question, 4).Return(answers, nil)
+	dbService.On("SetState", mock.Anything, golearn.State{
+		ID:        "5c6e5a1b",
+		UserKey:   update.UserID,
+		Question:  question,
+		Answers:   answers,
+		Direction: golearn.DirectionForward,
+		Timestamp: now().Unix(),
+	}).Return(nil)
+
+	message, markup, err := engine.startWithPickingMode(newRequest(&update, user, now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, nil, err)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestStart(t *testing.T) {
+	engine = New(Config{
+		DBService: nil,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	user := golearn.User{
+		Mode: "set no existed mode",
+	}
+
+	sampleError := fmt.Errorf("failed to start, undefined mode for user: %v", user)
+
+	message, markup, err := engine.start(newRequest(&update, user, time.Now))
+
+	assert.Equal(t, "", message)
+	assert.Equal(t, Keyboard{}, markup)
+	assert.Equal(t, sampleError, err)
+}
+
+func TestAnswerCallback(t *testing.T) {
+	state := golearn.State{
+		ID:      "5c6e5a1b",
+		UserKey: "177374215",
+		Question: golearn.Row{
+			Word:      "question word",
+			Translate: "question translate",
+		},
+		Answers: []golearn.Row{
+			{
+				Word:      "answer word",
+				Translate: "answer translate",
+			},
+			{
+				Word:      "question word",
+				Translate: "question translate",
+			},
+		},
+		Direction: golearn.DirectionForward,
+	}
+
+	now := func() time.Time {
+		return time.Date(2019, 02, 21, 0, 0, 0, 0, time.UTC)
+	}
+
+	nextWord := buttons(
+		[]string{lang["next_word"]},
+	)
+
+	testCases := map[string]struct {
+		Data     string
+		Answer   string
+		IsRight  bool
+		Message  string
+		Markup   Keyboard
+		IsError  bool
+		Answered bool
+	}{
+		"right option": {
+			Data:     "answer:5c6e5a1b:1",
+			Answer:   "question translate",
+			IsRight:  true,
+			Message:  lang["right"],
+			Markup:   nextWord,
+			Answered: true,
+		},
+		"wrong option": {
+			Data:    "answer:5c6e5a1b:0",
+			Answer:  "answer translate",
+			IsRight: false,
+			Message: lang["wrong"],
+			Markup: buttons(
+				[]string{lang["next_word"]},
+				[]string{lang["again"]},
+			),
+			Answered: true,
+		},
+		"button of previous question": {
+			Data:    "answer:0a1b2c3d:1",
+			Message: lang["question_expired"],
+			Markup: buttons(
+				[]string{lang["start"], lang["statistics"]},
+				[]string{lang["settings"], lang["help"]},
+			),
+		},
+		"option out of range": {
+			Data:    "answer:5c6e5a1b:4",
+			Message: lang["question_expired"],
+			Markup: buttons(
+				[]string{lang["start"], lang["statistics"]},
+				[]string{lang["settings"], lang["help"]},
+			),
+		},
+		"invalid data": {
+			Data:    "answer:5c6e5a1b",
+			Message: "",
+			Markup:  Keyboard{},
+			IsError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+			user := golearn.User{
+				UserID: "177374215",
+				Mode:   golearn.ModePicking,
+			}
+
+			update := golearn.Update{
+				ChatID:     "177374215",
+				UserID:     "177374215",
+				Message:    tc.Data,
+				CallbackID: "761370893423543219",
+			}
+			req := newRequest(&update, user, now)
+
+			if !tc.IsError {
+				dbService.On("GetState", mock.Anything, update.UserID).Return(state, nil)
+			}
+
+			if tc.Answered {
+				activity := golearn.Activity{
+					UserID:     update.UserID,
+					State:      state,
+					Answer:     tc.Answer,
+					IsRight:    tc.IsRight,
+					Verdict:    engine.checkAnswer(req, state, tc.Answer),
+					Similarity: golearn.JamoSimilarity("question translate", tc.Answer),
+					Timestamp:  now(),
+				}
+
+				review := golearn.NewReview(update.UserID, state.Question)
+
+				dbService.On("InsertActivity", mock.Anything, activity).Return(nil)
+				dbService.On("GetReview", mock.Anything, update.UserID, state.Question).Return(review, nil)
+				dbService.On("SetReview", mock.Anything, review.Schedule(activity.Quality(), now())).Return(nil)
+
+				answered := state
+				answered.Answered = true
+				answered.Timestamp = now().Unix()
+				dbService.On("SetState", mock.Anything, answered).Return(nil)
+			}
+
+			message, markup, err := engine.answerCallback(req)
+
+			assert.Equal(t, tc.IsError, err != nil)
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAnswerCallbackOnce presses the same answer button twice, the answer is graded only once.
+func TestAnswerCallbackOnce(t *testing.T) {
+	db := memory.New(memory.Config{
+		Words: []golearn.Row{
+			{Word: "word 1", Translate: "translate 1"},
+			{Word: "word 2", Translate: "translate 2"},
+			{Word: "word 3", Translate: "translate 3"},
+			{Word: "word 4", Translate: "translate 4"},
+		},
+	})
+
+	now := time.Date(2019, 02, 21, 10, 0, 0, 0, time.UTC)
+
+	engine = New(Config{
+		DBService: db,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+	engine.now = func() time.Time {
+		return now
+	}
+
+	ctx := context.Background()
+	send := func(message string, callbackID string) Reply {
+		reply, err := engine.Handle(ctx, &golearn.Update{
+			ChatID:     "177374215",
+			UserID:     "177374215",
+			Message:    message,
+			CallbackID: callbackID,
+		})
+		assert.Nil(t, err)
+		return reply
+	}
+
+	question := send(lang["start"], "")
+
+	state, err := db.GetState(ctx, "177374215")
+	assert.Nil(t, err)
+
+	var data string
+	for _, row := range question.Keyboard.Rows {
+		for _, button := range row {
+			if button.Text == state.AnswerOf(state.Question) {
+				data = button.Data
+			}
+		}
+	}
+
+	assert.Equal(t, lang["right"], send(data, "761370893423543219").Text)
+	assert.Equal(t, lang["question_expired"], send(data, "761370893423543220").Text)
+
+	review, err := db.GetReview(ctx, "177374215", state.Question)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, review.Repetitions)
+	assert.Equal(t, now.AddDate(0, 0, 1), review.Due)
+
+	_, week := now.ISOWeek()
+	statistics, err := db.GetStatistics(ctx, "177374215", now.Year(), int(now.Month()), week, now.Day())
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.StatRow{Total: 1, Right: 1}, statistics.Today)
+}
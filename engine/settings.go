@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergeiten/golearn"
+)
+
+// settings returns settings menu. It's limited to 10 buttons, so it fits quick replies
+// of KakaoTalk, options with several values are shown in own menus.
+func (e *Engine) settings(req *request) (message string, markup Keyboard, err error) {
+	// only the option which changes current value is shown
+	latinInput := req.lang["latin_input_on"]
+	if req.user.LatinInput {
+		latinInput = req.lang["latin_input_off"]
+	}
+
+	keyboard := buttons(
+		[]string{
+			req.lang["mode_picking"],
+			req.lang["mode_typing"],
+			req.lang["mode_leitner"],
+			req.lang["categories"],
+		},
+		[]string{
+			req.lang["direction_forward"],
+			req.lang["direction_reverse"],
+			req.lang["direction_mixed"],
+		},
+		[]string{
+			latinInput,
+			req.lang["answers"],
+		},
+		[]string{
+			req.lang["add_word"],
+		},
+	)
+
+	return req.lang["mode_explain"], keyboard, nil
+}
+
+func (e *Engine) statistics(req *request) (message string, markup Keyboard, err error) {
+	now := req.now()
+	year, month, day := now.Date()
+	// week is counted in its ISO year, storage gets it from the day
+	_, week := now.ISOWeek()
+
+	statistics, err := e.db.GetStatistics(req.ctx, req.update.UserID, year, int(month), week, day)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	message = req.lang["statistics_text"] + "\n\n"
+
+	message += req.lang["statistics_period_today"] + "\n"
+	message += fmt.Sprintf(req.lang["statistics_period_summary"], statistics.Today.Total, statistics.Today.Right, statistics.Today.Wrong) + "\n"
+
+	message += req.lang["statistics_period_week"] + "\n"
+	message += fmt.Sprintf(req.lang["statistics_period_summary"], statistics.Week.Total, statistics.Week.Right, statistics.Week.Wrong) + "\n"
+
+	message += req.lang["statistics_period_month"] + "\n"
+	message += fmt.Sprintf(req.lang["statistics_period_summary"], statistics.Month.Total, statistics.Month.Right, statistics.Month.Wrong)
+
+	boxes, err := e.db.GetBoxes(req.ctx, req.update.UserID)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	// boxes are shown only to users who have ever learned words in leitner mode
+	if len(boxes) > 0 {
+		words := make([]int, golearn.LeitnerBoxes+1)
+		for _, box := range boxes {
+			if box.Number > 0 && box.Number <= golearn.LeitnerBoxes {
+				words[box.Number] = box.Words
+			}
+		}
+
+		message += "\n" + req.lang["statistics_boxes"] + "\n"
+		for number := 1; number <= golearn.LeitnerBoxes; number++ {
+			message += fmt.Sprintf(req.lang["statistics_box"], number, golearn.LeitnerInterval(number), words[number]) + "\n"
+		}
+	}
+
+	return message, e.mainMenuKeyboard(req.lang), nil
+}
+
+func (e *Engine) categories(req *request) (message string, markup Keyboard, err error) {
+	return e.categoriesPage(req, 1)
+}
+
+// categoriesPage returns passed page of categories menu. Categories are paged only if menu
+// has more buttons than engine limit, the last page leads to the first one.
+func (e *Engine) categoriesPage(req *request, page int) (message string, markup Keyboard, err error) {
+	categories, err := e.db.GetCategories(req.ctx, req.update.UserID)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	var options []string
+	for _, a := range categories {
+		options = append(options, req.lang["categories_icon"]+" "+a.Name)
+	}
+
+	navigation := []string{
+		req.lang["reset_category"],
+		req.lang["main_menu"],
+	}
+
+	if e.maxButtons > 0 && len(options)+len(navigation) > e.maxButtons {
+		// navigation row keeps more button too
+		size := e.maxButtons - len(navigation) - 1
+		if size < 1 {
+			size = 1
+		}
+
+		pages := (len(options) + size - 1) / size
+		if page > pages {
+			page = pages
+		}
+
+		start := (page - 1) * size
+		finish := start + size
+		if finish > len(options) {
+			finish = len(options)
+		}
+
+		options = options[start:finish]
+		navigation = append(navigation, fmt.Sprintf(req.lang["categories_more"], page%pages+1))
+	}
+
+	var keyboard [][]string
+	for start := 0; start < len(options); start += e.cols {
+		finish := start + e.cols
+		if finish > len(options) {
+			finish = len(options)
+		}
+
+		keyboard = append(keyboard, options[start:finish])
+	}
+
+	keyboard = append(keyboard, navigation)
+
+	return req.lang["pick_category"], buttons(keyboard...), nil
+}
+
+// morePage returns page of categories requested by more button, zero if text isn't the button.
+func morePage(lang golearn.Language, text string) int {
+	var page int
+
+	_, err := fmt.Sscanf(text, lang["categories_more"], &page)
+	if err != nil || page < 1 {
+		return 0
+	}
+
+	return page
+}
+
+func (e *Engine) setCategory(req *request) (message string, markup Keyboard, err error) {
+	// remove category icon
+	category := strings.Trim(strings.Replace(req.update.Message, req.lang["categories_icon"], "", -1), " ")
+	err = e.db.SetUserCategory(req.ctx, req.user.UserID, category)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	return req.lang["category_set"], keyboard, nil
+}
+
+func (e *Engine) resetCategory(req *request) (message string, markup Keyboard, err error) {
+	err = e.db.SetUserCategory(req.ctx, req.user.UserID, "")
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	return req.lang["category_reset"], keyboard, nil
+}
+
+func (e *Engine) setMode(req *request, mode string) (message string, markup Keyboard, err error) {
+	err = e.db.SetUserMode(req.ctx, req.user.UserID, mode)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	return req.lang["mode_set"], keyboard, nil
+}
+
+func (e *Engine) setDirection(req *request, direction string) (message string, markup Keyboard, err error) {
+	err = e.db.SetUserDirection(req.ctx, req.user.UserID, direction)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	return req.lang["direction_set"], keyboard, nil
+}
+
+func (e *Engine) setLatinInput(req *request, enabled bool) (message string, markup Keyboard, err error) {
+	err = e.db.SetUserLatinInput(req.ctx, req.user.UserID, enabled)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	if !enabled {
+		return req.lang["latin_input_disabled"], keyboard, nil
+	}
+
+	return req.lang["latin_input_enabled"], keyboard, nil
+}
+
+// answers returns menu of count of answers in picking mode.
+func (e *Engine) answers(req *request) (message string, markup Keyboard, err error) {
+	keyboard := buttons(
+		[]string{
+			req.lang["answers_3"],
+			req.lang["answers_4"],
+			req.lang["answers_6"],
+		},
+		[]string{
+			req.lang["main_menu"],
+		},
+	)
+
+	return req.lang["answers_explain"], keyboard, nil
+}
+
+func (e *Engine) setAnswers(req *request, count int) (message string, markup Keyboard, err error) {
+	err = e.db.SetUserAnswers(req.ctx, req.user.UserID, count)
+	if err != nil {
+		return "", Keyboard{}, err
+	}
+
+	keyboard := e.mainMenuKeyboard(req.lang)
+
+	return fmt.Sprintf(req.lang["answers_set"], count), keyboard, nil
+}
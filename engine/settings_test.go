@@ -0,0 +1,712 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSettings(t *testing.T) {
+	dbService := &mocks.DBService{}
+
+	engine = New(Config{
+		DBService: dbService,
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	update := golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "command",
+	}
+
+	expectedMessage := lang["mode_explain"]
+	expectedMarkup := buttons(
+		[]string{
+			lang["mode_picking"],
+			lang["mode_typing"],
+			lang["mode_leitner"],
+			lang["categories"],
+		},
+		[]string{
+			lang["direction_forward"],
+			lang["direction_reverse"],
+			lang["direction_mixed"],
+		},
+		[]string{
+			lang["latin_input_on"],
+			lang["answers"],
+		},
+		[]string{
+			lang["add_word"],
+		},
+	)
+
+	message, markup, err := engine.settings(newRequest(&update, golearn.User{}, time.Now))
+
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, expectedMarkup, markup)
+	assert.Equal(t, nil, err)
+
+	// settings fit quick replies of KakaoTalk
+	count := 0
+	for _, row := range markup.Rows {
+		count += len(row)
+	}
+	assert.True(t, count <= 10, "settings have %d buttons", count)
+
+	// latin input can be turned off if it's on
+	_, markup, err = engine.settings(newRequest(&update, golearn.User{LatinInput: true}, time.Now))
+
+	assert.Equal(t, []Button{{Text: lang["latin_input_off"]}, {Text: lang["answers"]}}, markup.Rows[2])
+	assert.Equal(t, nil, err)
+}
+
+func TestAnswers(t *testing.T) {
+	engine = New(Config{
+		DBService: &mocks.DBService{},
+		Lang:      lang,
+		ColsCount: 2,
+	})
+
+	message, markup, err := engine.answers(newRequest(&golearn.Update{UserID: "177374215"}, golearn.User{}, time.Now))
+
+	assert.Equal(t, lang["answers_explain"], message)
+	assert.Equal(t, buttons(
+		[]string{
+			lang["answers_3"],
+			lang["answers_4"],
+			lang["answers_6"],
+		},
+		[]string{
+			lang["main_menu"],
+		},
+	), markup)
+	assert.Nil(t, err)
+}
+
+func TestSetMode(t *testing.T) {
+	testCases := map[string]struct {
+		User    golearn.User
+		Mode    string
+		Message string
+		Markup  Keyboard
+		Error   error
+	}{
+		"set mode with no error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Mode:    golearn.ModeTyping,
+			Message: lang["mode_set"],
+			Markup: buttons(
+				[]string{
+					lang["start"],
+					lang["statistics"],
+				},
+				[]string{
+					lang["settings"],
+					lang["help"],
+				},
+			),
+			Error: nil,
+		},
+		"set mode with error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Mode:    golearn.ModeTyping,
+			Message: "",
+			Markup:  Keyboard{},
+			Error:   errors.New("sample error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("SetUserMode", mock.Anything, tc.User.UserID, tc.Mode).Return(tc.Error)
+
+			message, markup, err := engine.setMode(newRequest(&golearn.Update{UserID: tc.User.UserID}, tc.User, time.Now), tc.Mode)
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetDirection(t *testing.T) {
+	testCases := map[string]struct {
+		User      golearn.User
+		Direction string
+		Message   string
+		Markup    Keyboard
+		Error     error
+	}{
+		"set direction with no error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Direction: golearn.DirectionMixed,
+			Message:   lang["direction_set"],
+			Markup: buttons(
+				[]string{
+					lang["start"],
+					lang["statistics"],
+				},
+				[]string{
+					lang["settings"],
+					lang["help"],
+				},
+			),
+			Error: nil,
+		},
+		"set direction with error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Direction: golearn.DirectionReverse,
+			Message:   "",
+			Markup:    Keyboard{},
+			Error:     errors.New("sample error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("SetUserDirection", mock.Anything, tc.User.UserID, tc.Direction).Return(tc.Error)
+
+			message, markup, err := engine.setDirection(newRequest(&golearn.Update{UserID: tc.User.UserID}, tc.User, time.Now), tc.Direction)
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetCategory(t *testing.T) {
+	testCases := map[string]struct {
+		User     golearn.User
+		Update   *golearn.Update
+		Category string
+		Message  string
+		Markup   Keyboard
+		Error    error
+	}{
+		"set category with no error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Update: &golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "2019-01-01",
+			},
+			Category: "2019-01-01",
+			Message:  lang["category_set"],
+			Markup: buttons(
+				[]string{
+					lang["start"],
+					lang["statistics"],
+				},
+				[]string{
+					lang["settings"],
+					lang["help"],
+				},
+			),
+			Error: nil,
+		},
+		"set category with error": {
+			User: golearn.User{
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Mode:     golearn.ModePicking,
+			},
+			Update: &golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "2019-01-01",
+			},
+			Category: "2019-01-01",
+			Message:  "",
+			Markup:   Keyboard{},
+			Error:    errors.New("sample error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("SetUserCategory", mock.Anything, tc.User.UserID, tc.Category).Return(tc.Error)
+
+			message, markup, err := engine.setCategory(newRequest(tc.Update, tc.User, time.Now))
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategories(t *testing.T) {
+	testCases := map[string]struct {
+		Cols       int
+		Update     *golearn.Update
+		Categories []golearn.Category
+		Message    string
+		Markup     Keyboard
+		Error      error
+	}{
+		"categories with no error": {
+			Cols: 2,
+			Update: &golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "",
+			},
+			Categories: []golearn.Category{
+				{
+					Name:  "2019-01-01",
+					Words: 10,
+				},
+				{
+					Name:  "2019-01-02",
+					Words: 15,
+				},
+			},
+			Message: lang["pick_category"],
+			Markup: buttons(
+				[]string{
+					lang["categories_icon"] + " 2019-01-01",
+					lang["categories_icon"] + " 2019-01-02",
+				},
+				[]string{
+					lang["reset_category"],
+					lang["main_menu"],
+				},
+			),
+			Error: nil,
+		},
+		"categories without cols count": {
+			Cols: 0,
+			Update: &golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "",
+			},
+			Categories: []golearn.Category{
+				{
+					Name:  "2019-01-01",
+					Words: 10,
+				},
+				{
+					Name:  "2019-01-02",
+					Words: 15,
+				},
+			},
+			Message: lang["pick_category"],
+			Markup: buttons(
+				[]string{
+					lang["categories_icon"] + " 2019-01-01",
+				},
+				[]string{
+					lang["categories_icon"] + " 2019-01-02",
+				},
+				[]string{
+					lang["reset_category"],
+					lang["main_menu"],
+				},
+			),
+			Error: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: tc.Cols,
+			})
+
+			dbService.On("GetCategories", mock.Anything, tc.Update.UserID).Return(tc.Categories, tc.Error)
+
+			message, markup, err := engine.categories(newRequest(tc.Update, golearn.User{}, time.Now))
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoriesPages(t *testing.T) {
+	update := &golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "",
+	}
+
+	var categories []golearn.Category
+	var options []string
+	for i := 1; i <= 12; i++ {
+		name := fmt.Sprintf("2019-01-%02d", i)
+		categories = append(categories, golearn.Category{Name: name, Words: 10})
+		options = append(options, lang["categories_icon"]+" "+name)
+	}
+
+	testCases := map[string]struct {
+		Markup Keyboard
+	}{
+		lang["categories"]: {
+			Markup: buttons(
+				options[0:2],
+				options[2:4],
+				options[4:6],
+				options[6:7],
+				[]string{lang["reset_category"], lang["main_menu"], fmt.Sprintf(lang["categories_more"], 2)},
+			),
+		},
+		fmt.Sprintf(lang["categories_more"], 2): {
+			Markup: buttons(
+				options[7:9],
+				options[9:11],
+				options[11:12],
+				[]string{lang["reset_category"], lang["main_menu"], fmt.Sprintf(lang["categories_more"], 1)},
+			),
+		},
+		fmt.Sprintf(lang["categories_more"], 5): {
+			Markup: buttons(
+				options[7:9],
+				options[9:11],
+				options[11:12],
+				[]string{lang["reset_category"], lang["main_menu"], fmt.Sprintf(lang["categories_more"], 1)},
+			),
+		},
+	}
+
+	for message, tc := range testCases {
+		t.Run(message, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService:  dbService,
+				Lang:       lang,
+				ColsCount:  2,
+				MaxButtons: 10,
+			})
+
+			dbService.On("GetCategories", mock.Anything, update.UserID).Return(categories, nil)
+
+			page := morePage(lang, message)
+			if page == 0 {
+				page = 1
+			}
+
+			text, markup, err := engine.categoriesPage(newRequest(update, golearn.User{}, time.Now), page)
+
+			assert.Nil(t, err)
+			assert.Equal(t, lang["pick_category"], text)
+			assert.Equal(t, tc.Markup, markup)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMorePage(t *testing.T) {
+	testCases := map[string]int{
+		fmt.Sprintf(lang["categories_more"], 1):  1,
+		fmt.Sprintf(lang["categories_more"], 12): 12,
+		fmt.Sprintf(lang["categories_more"], 0):  0,
+		lang["categories"]:                       0,
+		"":                                       0,
+	}
+
+	for text, expected := range testCases {
+		t.Run(text, func(t *testing.T) {
+			assert.Equal(t, expected, morePage(lang, text))
+		})
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	update := &golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  lang["statistics"],
+	}
+
+	statistics := golearn.Statistics{
+		Today: golearn.StatRow{Total: 3, Right: 1, Wrong: 2},
+		Week:  golearn.StatRow{Total: 9, Right: 3, Wrong: 6},
+		Month: golearn.StatRow{Total: 11, Right: 5, Wrong: 6},
+	}
+
+	summary := lang["statistics_text"] + "\n\n" +
+		lang["statistics_period_today"] + "\n" +
+		fmt.Sprintf(lang["statistics_period_summary"], 3, 1, 2) + "\n" +
+		lang["statistics_period_week"] + "\n" +
+		fmt.Sprintf(lang["statistics_period_summary"], 9, 3, 6) + "\n" +
+		lang["statistics_period_month"] + "\n" +
+		fmt.Sprintf(lang["statistics_period_summary"], 11, 5, 6)
+
+	testCases := map[string]struct {
+		Boxes   []golearn.Box
+		Message string
+		Error   error
+	}{
+		"without boxes": {
+			Boxes:   []golearn.Box{},
+			Message: summary,
+			Error:   nil,
+		},
+		"with boxes": {
+			Boxes: []golearn.Box{
+				{Number: 1, Words: 7},
+				{Number: 3, Words: 2},
+			},
+			Message: summary + "\n" + lang["statistics_boxes"] + "\n" +
+				fmt.Sprintf(lang["statistics_box"], 1, 1, 7) + "\n" +
+				fmt.Sprintf(lang["statistics_box"], 2, 2, 0) + "\n" +
+				fmt.Sprintf(lang["statistics_box"], 3, 4, 2) + "\n" +
+				fmt.Sprintf(lang["statistics_box"], 4, 8, 0) + "\n" +
+				fmt.Sprintf(lang["statistics_box"], 5, 16, 0) + "\n",
+			Error: nil,
+		},
+		"with boxes error": {
+			Boxes:   nil,
+			Message: "",
+			Error:   errors.New("sample error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("GetStatistics", mock.Anything, update.UserID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(statistics, nil)
+			dbService.On("GetBoxes", mock.Anything, update.UserID).Return(tc.Boxes, tc.Error)
+
+			message, _, err := engine.statistics(newRequest(update, golearn.User{}, time.Now))
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetLatinInput(t *testing.T) {
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModeTyping,
+	}
+
+	mainMenu := buttons(
+		[]string{
+			lang["start"],
+			lang["statistics"],
+		},
+		[]string{
+			lang["settings"],
+			lang["help"],
+		},
+	)
+
+	testCases := map[string]struct {
+		Enabled bool
+		Message string
+		Markup  Keyboard
+		Error   error
+	}{
+		"enable latin input": {
+			Enabled: true,
+			Message: lang["latin_input_enabled"],
+			Markup:  mainMenu,
+			Error:   nil,
+		},
+		"disable latin input": {
+			Enabled: false,
+			Message: lang["latin_input_disabled"],
+			Markup:  mainMenu,
+			Error:   nil,
+		},
+		"set latin input with error": {
+			Enabled: true,
+			Message: "",
+			Markup:  Keyboard{},
+			Error:   errors.New("sample error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("SetUserLatinInput", mock.Anything, user.UserID, tc.Enabled).Return(tc.Error)
+
+			message, markup, err := engine.setLatinInput(newRequest(&golearn.Update{UserID: user.UserID}, user, time.Now), tc.Enabled)
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetAnswers(t *testing.T) {
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+	}
+
+	mainMenu := buttons(
+		[]string{
+			lang["start"],
+			lang["statistics"],
+		},
+		[]string{
+			lang["settings"],
+			lang["help"],
+		},
+	)
+
+	testCases := map[string]struct {
+		Count   int
+		Message string
+		Markup  Keyboard
+		Error   error
+	}{
+		"set 3 answers": {
+			Count:   3,
+			Message: fmt.Sprintf(lang["answers_set"], 3),
+			Markup:  mainMenu,
+			Error:   nil,
+		},
+		"set 6 answers": {
+			Count:   6,
+			Message: fmt.Sprintf(lang["answers_set"], 6),
+			Markup:  mainMenu,
+			Error:   nil,
+		},
+		"set answers with error": {
+			Count:   4,
+			Message: "",
+			Markup:  Keyboard{},
+			Error:   errors.New("sample error"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			engine = New(Config{
+				DBService: dbService,
+				Lang:      lang,
+				ColsCount: 2,
+			})
+
+			dbService.On("SetUserAnswers", mock.Anything, user.UserID, tc.Count).Return(tc.Error)
+
+			message, markup, err := engine.setAnswers(newRequest(&golearn.Update{UserID: user.UserID}, user, time.Now), tc.Count)
+
+			assert.Equal(t, tc.Message, message)
+			assert.Equal(t, tc.Markup, markup)
+			assert.Equal(t, tc.Error, err)
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
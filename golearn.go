@@ -1,20 +1,39 @@
 package golearn
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"log"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
+// ErrNoWords is returned by DBService if there is no word to ask, e.g. chosen category is empty.
+var ErrNoWords = errors.New("no words")
+
 // ModeTyping constant for user "typing" mode
 const ModeTyping = "typing"
 
 // ModePicking constant for user "picking" mode
 const ModePicking = "picking"
 
+// ModeLeitner constant for user "leitner" mode
+const ModeLeitner = "leitner"
+
+// StepWord constant for add word conversation waiting for the word
+const StepWord = "word"
+
+// StepTranslate constant for add word conversation waiting for translation of the word
+const StepTranslate = "translate"
+
+// StepCollection constant for add word conversation waiting for name of collection
+const StepCollection = "collection"
+
 // LogFormatter ...
 type LogFormatter struct{}
 
@@ -27,6 +46,12 @@ type Row struct {
 	Word      string
 	Translate string
 	Category  string
+	// Spellings and Translations are accepted variants of the word and its translation,
+	// set only if there are several of them. The first variants are Word and Translate.
+	Spellings    []string `bson:",omitempty"`
+	Translations []string `bson:",omitempty"`
+	// Owner is id of user who has added the word to own collection, empty for global words
+	Owner string `bson:",omitempty"`
 }
 
 // Category represents category model.
@@ -35,13 +60,33 @@ type Category struct {
 	Words int
 }
 
+// Box represents Leitner box with count of user words in it.
+type Box struct {
+	Number int
+	Words  int
+}
+
 // User represents user model
 type User struct {
-	UserID   string
-	Username string
-	Name     string
-	Mode     string
-	Category string
+	UserID    string
+	Username  string
+	Name      string
+	Mode      string
+	Category  string
+	Direction string
+	// LatinInput enables conversion of answers typed on Latin keyboard to Hangul
+	LatinInput bool
+	// Answers is count of answers in picking mode, DefaultAnswers is used if it's zero
+	Answers int
+}
+
+// AnswersCount returns count of answers in picking mode chosen by user.
+func (u User) AnswersCount() int {
+	if u.Answers > 0 {
+		return u.Answers
+	}
+
+	return DefaultAnswers
 }
 
 // Update represents joint response data model from service (telegram, kakaotalk).
@@ -52,26 +97,37 @@ type Update struct {
 	Username string
 	Name     string
 	Message  string
+	// CallbackID is id of callback query if update is press of inline button
+	CallbackID string
 }
 
 // State represents last user state by saving question and answers in db.
 // When user answers we get last state and compare text user send with state answer
 type State struct {
+	// ID identifies question, so buttons of previous questions can be told apart
+	ID        string `bson:",omitempty"`
 	UserKey   string
 	Question  Row
 	Answers   []Row
 	Mode      string
 	Category  string
+	Direction string
+	// Step is set while user adds word to own collection, Question keeps the word being added
+	Step string `bson:",omitempty"`
+	// Answered is set when answer is graded, so the question can't be answered once more
+	Answered  bool `bson:",omitempty"`
 	Timestamp int64
 }
 
 // Activity represents user activity.
 type Activity struct {
-	UserID    string
-	State     State
-	Answer    string
-	IsRight   bool
-	Timestamp time.Time
+	UserID     string
+	State      State
+	Answer     string
+	IsRight    bool
+	Verdict    Verdict
+	Similarity float64
+	Timestamp  time.Time
 }
 
 // StatRow represents user statistics for specific period.
@@ -88,31 +144,58 @@ type Statistics struct {
 	Month StatRow `json:"month"`
 }
 
+// WeekYear returns ISO 8601 year of passed day, ISO week of the day belongs to this year.
+// It differs from the calendar year for a few days around new year, e.g. 30 Dec 2019 is in the first week of 2020.
+func WeekYear(year int, month int, day int) int {
+	weekYear, _ := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).ISOWeek()
+
+	return weekYear
+}
+
 // DBService ...
 type DBService interface {
-	RandomQuestion(category string) (Row, error)
-	RandomAnswers(q Row, limit int) ([]Row, error)
-	SetState(State) error
-	GetState(string) (State, error)
-	ResetState(string) error
-	InsertWord(Row) error
-	InsertUser(user User) error
-	UpdateUser(user User) error
-	ExistUser(user User) (bool, error)
-	GetUser(userID string) (User, error)
-	SetUserMode(userID string, mode string) error
-	GetCategories(userID string) ([]Category, error)
-	SetUserCategory(userID string, category string) error
-	DeleteWordsByCategory(userID string, category string) error
-	InsertActivity(activity Activity) error
-	GetStatistics(userID string, year int, month int, week int, day int) (Statistics, error)
+	RandomQuestion(ctx context.Context, userID string, category string, exclude []string) (Row, error)
+	NextDueQuestion(ctx context.Context, userID string, category string, now time.Time, exclude []string) (Row, error)
+	RecentWords(ctx context.Context, userKey string, limit int) ([]string, error)
+	RandomAnswers(ctx context.Context, userID string, q Row, limit int) ([]Row, error)
+	SetState(ctx context.Context, state State) error
+	GetState(ctx context.Context, userKey string) (State, error)
+	ResetState(ctx context.Context, userKey string) error
+	InsertWord(ctx context.Context, row Row) error
+	InsertUser(ctx context.Context, user User) error
+	UpdateUser(ctx context.Context, user User) error
+	ExistUser(ctx context.Context, user User) (bool, error)
+	GetUser(ctx context.Context, userID string) (User, error)
+	SetUserMode(ctx context.Context, userID string, mode string) error
+	SetUserDirection(ctx context.Context, userID string, direction string) error
+	SetUserLatinInput(ctx context.Context, userID string, enabled bool) error
+	SetUserAnswers(ctx context.Context, userID string, count int) error
+	GetCategories(ctx context.Context, userID string) ([]Category, error)
+	SetUserCategory(ctx context.Context, userID string, category string) error
+	DeleteWordsByCategory(ctx context.Context, userID string, category string) error
+	InsertActivity(ctx context.Context, activity Activity) error
+	GetStatistics(ctx context.Context, userID string, year int, month int, week int, day int) (Statistics, error)
+	GetReview(ctx context.Context, userID string, row Row) (Review, error)
+	SetReview(ctx context.Context, review Review) error
+	GetBoxes(ctx context.Context, userID string) ([]Box, error)
 	Close()
 }
 
 // HTTPService represents interface for dealing with sending and parsing http requests
+// Parse doesn't take context, it has the one of parsed request.
 type HTTPService interface {
-	Send(update *Update, message string, keyboard string) error
+	Send(ctx context.Context, update *Update, message string, keyboard string) error
 	Parse(r *http.Request) (*Update, error)
+	AnswerCallback(ctx context.Context, update *Update) error
+}
+
+// NewStateID returns random id of new state.
+func NewStateID() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	LogPrint(err, "failed to generate state id")
+
+	return hex.EncodeToString(b)
 }
 
 // GetLanguage returns language object with phrases
@@ -126,6 +209,32 @@ func GetLanguage(content []byte) (Language, error) {
 	return lang, nil
 }
 
+// GetWords returns words of word list content, it maps category name to pairs of word and translate.
+// Words are sorted by category name and keep order of the list inside of category.
+func GetWords(content []byte) ([]Row, error) {
+	var list map[string][][2]string
+	err := json.Unmarshal(content, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]string, 0, len(list))
+	for category := range list {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var rows []Row
+	for _, category := range categories {
+		for _, pair := range list[category] {
+			// word and translate may contain several accepted variants separated by semicolon
+			rows = append(rows, NewRow(pair[0], pair[1], category))
+		}
+	}
+
+	return rows, nil
+}
+
 // LogPrint prints error message with stack trace without exited program.
 func LogPrint(err error, message string) {
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetLanguage(t *testing.T) {
@@ -36,3 +38,75 @@ func TestGetLanguage(t *testing.T) {
 		}
 	})
 }
+
+func TestGetWords(t *testing.T) {
+	tests := map[string]struct {
+		Content string
+		Words   []Row
+		Err     bool
+	}{
+		"words sorted by category": {
+			Content: `{"b": [["word 2", "translate 2"], ["word 3", "translate 3"]], "a": [["word 1", "translate 1"]]}`,
+			Words: []Row{
+				{Word: "word 1", Translate: "translate 1", Category: "a"},
+				{Word: "word 2", Translate: "translate 2", Category: "b"},
+				{Word: "word 3", Translate: "translate 3", Category: "b"},
+			},
+		},
+		"words with variants": {
+			Content: `{"a": [["word; spelling", "translate"]]}`,
+			Words: []Row{
+				{Word: "word", Translate: "translate", Category: "a", Spellings: []string{"word", "spelling"}},
+			},
+		},
+		"invalid content": {
+			Content: `["word", "translate"]`,
+			Err:     true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			words, err := GetWords([]byte(tc.Content))
+
+			assert.Equal(t, tc.Err, err != nil)
+			assert.Equal(t, tc.Words, words)
+		})
+	}
+
+	t.Run("bundled word list", func(t *testing.T) {
+		content, err := ioutil.ReadFile("./words.json")
+		if err != nil {
+			t.Fatalf("failed to read word list: %v", err)
+		}
+
+		words, err := GetWords(content)
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, words)
+		for _, w := range words {
+			assert.NotEmpty(t, w.Word)
+			assert.NotEmpty(t, w.Translate)
+			assert.NotEmpty(t, w.Category)
+		}
+	})
+}
+
+func TestWeekYear(t *testing.T) {
+	tests := map[string]struct {
+		Date     [3]int
+		Expected int
+	}{
+		"middle of year":                 {[3]int{2019, 2, 22}, 2019},
+		"end of year in next year":       {[3]int{2019, 12, 30}, 2020},
+		"end of year":                    {[3]int{2019, 12, 29}, 2019},
+		"start of year in previous year": {[3]int{2021, 1, 3}, 2020},
+		"start of year":                  {[3]int{2020, 1, 1}, 2020},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, WeekYear(tc.Date[0], tc.Date[1], tc.Date[2]))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package golearn
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	syllableBase  = 0xAC00
+	syllableLast  = 0xD7A3
+	medialsCount  = 21
+	finalsCount   = 28
+	syllableBlock = medialsCount * finalsCount
+)
+
+// Hangul compatibility jamo in order of their indexes in precomposed syllables.
+var (
+	initials = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	medials  = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	finals   = []rune("\x00ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ")
+)
+
+// Jamo represents Hangul syllable decomposed to initial consonant, medial vowel
+// and optional final consonant (batchim). Final is zero for syllables without batchim.
+type Jamo struct {
+	Initial rune
+	Medial  rune
+	Final   rune
+}
+
+// IsSyllable returns true if passed rune is precomposed Hangul syllable.
+func IsSyllable(r rune) bool {
+	return r >= syllableBase && r <= syllableLast
+}
+
+// Decompose returns jamo of passed Hangul syllable.
+// false is returned if passed rune is not Hangul syllable.
+func Decompose(r rune) (Jamo, bool) {
+	if !IsSyllable(r) {
+		return Jamo{}, false
+	}
+
+	index := int(r - syllableBase)
+
+	return Jamo{
+		Initial: initials[index/syllableBlock],
+		Medial:  medials[index%syllableBlock/finalsCount],
+		Final:   finals[index%finalsCount],
+	}, true
+}
+
+// Jamos returns passed string with Hangul syllables split to jamo.
+// Other characters are left as is.
+func Jamos(s string) []rune {
+	var jamos []rune
+
+	for _, r := range norm.NFC.String(s) {
+		jamo, ok := Decompose(r)
+		if !ok {
+			jamos = append(jamos, r)
+			continue
+		}
+
+		jamos = append(jamos, jamo.Initial, jamo.Medial)
+		if jamo.Final != 0 {
+			jamos = append(jamos, jamo.Final)
+		}
+	}
+
+	return jamos
+}
+
+// JamoSimilarity returns similarity of passed strings from 0 (nothing in common)
+// to 1 (equal). Hangul is compared by jamo, so mixed up vowel or missed batchim
+// costs less than a whole wrong syllable.
+func JamoSimilarity(expected string, answer string) float64 {
+	e := Jamos(Normalize(expected))
+	a := Jamos(Normalize(answer))
+
+	longest := len(e)
+	if len(a) > longest {
+		longest = len(a)
+	}
+
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(Distance(string(e), string(a)))/float64(longest)
+}
+
+// HighlightDiff returns expected answer as HTML with characters (syllables for Hangul)
+// which user got wrong or missed wrapped in <b> tag.
+func HighlightDiff(expected string, answer string) string {
+	e := []rune(norm.NFC.String(expected))
+	a := []rune(norm.NFC.String(strings.TrimSpace(answer)))
+
+	wrong := diff(e, a)
+
+	var b strings.Builder
+	for i := 0; i < len(e); {
+		j := i
+		for j < len(e) && wrong[j] == wrong[i] {
+			j++
+		}
+
+		part := html.EscapeString(string(e[i:j]))
+		if wrong[i] {
+			part = "<b>" + part + "</b>"
+		}
+		b.WriteString(part)
+
+		i = j
+	}
+
+	return b.String()
+}
+
+// diff aligns answer with expected runes by minimal edit distance and returns
+// flags of expected runes which are substituted or deleted in answer.
+func diff(expected []rune, answer []rune) []bool {
+	// d[i][j] is edit distance between expected[:i] and answer[:j]
+	d := make([][]int, len(expected)+1)
+	for i := range d {
+		d[i] = make([]int, len(answer)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(expected); i++ {
+		for j := 1; j <= len(answer); j++ {
+			cost := 1
+			if unicode.ToLower(expected[i-1]) == unicode.ToLower(answer[j-1]) {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+
+	wrong := make([]bool, len(expected))
+
+	i, j := len(expected), len(answer)
+	for i > 0 {
+		switch {
+		case j > 0 && d[i][j] == d[i-1][j-1] && unicode.ToLower(expected[i-1]) == unicode.ToLower(answer[j-1]):
+			i--
+			j--
+		case j > 0 && d[i][j] == d[i-1][j-1]+1:
+			wrong[i-1] = true
+			i--
+			j--
+		case d[i][j] == d[i-1][j]+1:
+			wrong[i-1] = true
+			i--
+		default:
+			j--
+		}
+	}
+
+	return wrong
+}
@@ -0,0 +1,156 @@
+package golearn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompose(t *testing.T) {
+	testCases := map[string]struct {
+		Syllable rune
+		Jamo     Jamo
+		Ok       bool
+	}{
+		"first syllable": {
+			Syllable: '가',
+			Jamo:     Jamo{Initial: 'ㄱ', Medial: 'ㅏ'},
+			Ok:       true,
+		},
+		"last syllable": {
+			Syllable: '힣',
+			Jamo:     Jamo{Initial: 'ㅎ', Medial: 'ㅣ', Final: 'ㅎ'},
+			Ok:       true,
+		},
+		"syllable with batchim": {
+			Syllable: '한',
+			Jamo:     Jamo{Initial: 'ㅎ', Medial: 'ㅏ', Final: 'ㄴ'},
+			Ok:       true,
+		},
+		"syllable with double batchim": {
+			Syllable: '읽',
+			Jamo:     Jamo{Initial: 'ㅇ', Medial: 'ㅣ', Final: 'ㄺ'},
+			Ok:       true,
+		},
+		"compound vowel": {
+			Syllable: '왜',
+			Jamo:     Jamo{Initial: 'ㅇ', Medial: 'ㅙ'},
+			Ok:       true,
+		},
+		"not a syllable": {
+			Syllable: 'ㄱ',
+			Jamo:     Jamo{},
+			Ok:       false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			jamo, ok := Decompose(tc.Syllable)
+
+			assert.Equal(t, tc.Ok, ok)
+			assert.Equal(t, tc.Jamo, jamo)
+		})
+	}
+}
+
+func TestJamos(t *testing.T) {
+	assert.Equal(t, []rune("ㅎㅏㄴㄱㅡㄹ"), Jamos("한글"))
+	assert.Equal(t, []rune("ㅎㅏㄴㄱㅡㄹ"), Jamos("\u1112\u1161\u11ab\u1100\u1173\u11af"))
+	assert.Equal(t, []rune("ㅅㅓ ㅇㅜㄹ!"), Jamos("서 울!"))
+}
+
+func TestJamoSimilarity(t *testing.T) {
+	testCases := map[string]struct {
+		Expected   string
+		Answer     string
+		Similarity float64
+	}{
+		"equal": {
+			Expected:   "서울",
+			Answer:     "서울",
+			Similarity: 1,
+		},
+		"mixed up vowel": {
+			Expected:   "서울",
+			Answer:     "소울",
+			Similarity: 0.8,
+		},
+		"missed batchim": {
+			Expected:   "읽다",
+			Answer:     "익다",
+			Similarity: 0.8,
+		},
+		"missed syllable": {
+			Expected:   "한국어",
+			Answer:     "한국",
+			Similarity: 0.75,
+		},
+		"nothing in common": {
+			Expected:   "서울",
+			Answer:     "부산",
+			Similarity: 0,
+		},
+		"empty": {
+			Expected:   "",
+			Answer:     "",
+			Similarity: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, tc.Similarity, JamoSimilarity(tc.Expected, tc.Answer), 0.0001)
+		})
+	}
+}
+
+func TestHighlightDiff(t *testing.T) {
+	testCases := map[string]struct {
+		Expected string
+		Answer   string
+		HTML     string
+	}{
+		"right answer": {
+			Expected: "서울",
+			Answer:   "서울",
+			HTML:     "서울",
+		},
+		"wrong vowel": {
+			Expected: "서울",
+			Answer:   "소울",
+			HTML:     "<b>서</b>울",
+		},
+		"wrong batchim": {
+			Expected: "읽다",
+			Answer:   "익다",
+			HTML:     "<b>읽</b>다",
+		},
+		"missed syllable": {
+			Expected: "한국어",
+			Answer:   "한국",
+			HTML:     "한국<b>어</b>",
+		},
+		"extra syllable": {
+			Expected: "한국",
+			Answer:   "한국어",
+			HTML:     "한국",
+		},
+		"several wrong syllables in a row": {
+			Expected: "감사합니다",
+			Answer:   "감소한니다",
+			HTML:     "감<b>사합</b>니다",
+		},
+		"escaped html": {
+			Expected: "<a> & b",
+			Answer:   "<a> & c",
+			HTML:     "&lt;a&gt; &amp; <b>b</b>",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.HTML, HighlightDiff(tc.Expected, tc.Answer))
+		})
+	}
+}
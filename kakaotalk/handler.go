@@ -1,28 +1,33 @@
 package kakaotalk
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"math/rand"
 	"net/http"
-	"time"
 
 	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/engine"
 )
 
 // Handler ...
 type Handler struct {
-	service  golearn.DBService
-	lang     golearn.Language
+	engine   *engine.Engine
 	langCode string
 }
 
 // New returns new instance of Handler
 func New(cfg Config) *Handler {
 	return &Handler{
-		service:  cfg.Service,
-		lang:     cfg.Lang,
+		engine: engine.New(engine.Config{
+			DBService:   cfg.Service,
+			Lang:        cfg.Lang,
+			ColsCount:   cfg.ColsCount,
+			Matcher:     cfg.Matcher,
+			RecentWords: cfg.RecentWords,
+			MaxButtons:  maxQuickReplies,
+		}),
 		langCode: cfg.DefaultLanguage,
 	}
 }
@@ -44,6 +49,11 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.message(w, r)
 		return
 	}
+
+	if r.Method == http.MethodPost && r.URL.Path == "/kakaobot/skill" {
+		h.skill(w, r)
+		return
+	}
 }
 
 func (h *Handler) message(w http.ResponseWriter, r *http.Request) {
@@ -54,7 +64,7 @@ func (h *Handler) message(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	msg, err := h.prepareMessage(cmd)
+	msg, err := h.prepareMessage(r.Context(), cmd)
 	if err != nil {
 		golearn.LogPrintf(err, "failed to handle message %s", cmd)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -70,22 +80,22 @@ func (h *Handler) message(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	fmt.Fprintf(w, string(resp))
+	_, err = fmt.Fprint(w, string(resp))
+	golearn.LogPrint(err, "failed to send response")
 }
 
-func (h *Handler) prepareMessage(cmd *command) (*message, error) {
-	switch cmd.Content {
-	case h.lang["help"]:
-		return h.handleHelp()
-	case h.lang["start"]:
-		return h.handleStart(cmd)
-	case h.lang["next_word"]:
-		return h.handleStart(cmd)
-	case h.lang["again"]:
-		return h.handleAgain(cmd)
-	default:
-		return h.handleCommand(cmd)
+// prepareMessage passes command to engine and returns its reply as message.
+func (h *Handler) prepareMessage(ctx context.Context, cmd *command) (*message, error) {
+	reply, err := h.engine.Handle(ctx, &golearn.Update{
+		ChatID:  cmd.UserKey,
+		UserID:  cmd.UserKey,
+		Message: cmd.Content,
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	return newMessage(reply), nil
 }
 
 func (h *Handler) commandFromRequest(r *http.Request) (*command, error) {
@@ -103,13 +113,12 @@ func (h *Handler) commandFromRequest(r *http.Request) (*command, error) {
 }
 
 func (h *Handler) keyboard(w http.ResponseWriter, r *http.Request) {
-	resp, err := json.Marshal(&keyboard{
-		Type: typeButtons,
-		Buttons: []string{
-			h.lang["start"],
-			h.lang["help"],
-		},
+	// keyboard of the user is the same as keyboard of main menu
+	msg := newMessage(engine.Reply{
+		Keyboard: h.engine.MainMenuKeyboard(),
 	})
+
+	resp, err := json.Marshal(msg.Keyboard)
 	if err != nil {
 		golearn.LogPrint(err, "failed to marshal keyboard")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -120,103 +129,3 @@ func (h *Handler) keyboard(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Fprint(w, string(resp))
 }
-
-func (h *Handler) handleHelp() (*message, error) {
-	msg := &message{}
-
-	msg.Message.Text = h.lang["help_message"]
-	msg.Keyboard.Type = "buttons"
-	msg.Keyboard.Buttons = []string{
-		h.lang["start"],
-		h.lang["help"],
-	}
-
-	return msg, nil
-}
-
-// handleStart returns message for start action
-func (h *Handler) handleStart(cmd *command) (*message, error) {
-	user, err := h.service.GetUser(cmd.UserKey)
-	if err != nil {
-		return nil, err
-	}
-	m := &message{}
-	question, err := h.service.RandomQuestion(user.Category)
-	if err != nil {
-		return m, err
-	}
-
-	answers, err := h.service.RandomAnswers(question, 4)
-	if err != nil {
-		return m, err
-	}
-
-	// shuffle answers
-	shuffledAnswers := make([]golearn.Row, len(answers))
-	perm := rand.Perm(len(answers))
-	for i, v := range perm {
-		shuffledAnswers[v] = answers[i]
-	}
-
-	// prepare return message
-	m.Message.Text = question.Word
-	m.Keyboard.Type = "buttons"
-
-	for _, a := range shuffledAnswers {
-		m.Keyboard.Buttons = append(m.Keyboard.Buttons, a.Translate)
-	}
-
-	// save state
-	s := golearn.State{
-		UserKey:   cmd.UserKey,
-		Question:  question,
-		Answers:   shuffledAnswers,
-		Timestamp: time.Now().Unix(),
-	}
-
-	err = h.service.SetState(s)
-	return m, err
-}
-
-func (h *Handler) handleCommand(cmd *command) (*message, error) {
-	m := &message{}
-	state, err := h.service.GetState(cmd.UserKey)
-	if err != nil {
-		return m, err
-	}
-
-	isRight := h.isAnswerRight(state, cmd)
-
-	m.Keyboard.Type = typeButtons
-	m.Keyboard.Buttons = []string{
-		h.lang["next_word"],
-	}
-	if isRight {
-		m.Message.Text = h.lang["right"]
-	} else {
-		m.Message.Text = h.lang["wrong"]
-		m.Keyboard.Buttons = append(m.Keyboard.Buttons, h.lang["again"])
-	}
-
-	return m, nil
-}
-
-func (h *Handler) handleAgain(cmd *command) (*message, error) {
-	m := &message{}
-	state, err := h.service.GetState(cmd.UserKey)
-	if err != nil {
-		return m, err
-	}
-	m.Message.Text = state.Question.Word
-	m.Keyboard.Type = typeButtons
-
-	for _, b := range state.Answers {
-		m.Keyboard.Buttons = append(m.Keyboard.Buttons, b.Translate)
-	}
-
-	return m, nil
-}
-
-func (h *Handler) isAnswerRight(state golearn.State, cmd *command) bool {
-	return state.Question.Translate == cmd.Content
-}
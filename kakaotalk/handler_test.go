@@ -0,0 +1,340 @@
+package kakaotalk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/memory"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestKeyboard(t *testing.T) {
+	handler := New(Config{
+		Service: &mocks.DBService{},
+		Lang:    lang,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/kakaobot/keyboard", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	kb := keyboard{}
+	err := json.Unmarshal(w.Body.Bytes(), &kb)
+
+	assert.Nil(t, err)
+	assert.Equal(t, keyboard{
+		Type: typeButtons,
+		Buttons: []string{
+			lang["start"],
+			lang["statistics"],
+			lang["settings"],
+			lang["help"],
+		},
+	}, kb)
+}
+
+func TestMessage(t *testing.T) {
+	userKey := "Hg3bR0xq2Ztk"
+
+	user := golearn.User{
+		UserID: userKey,
+		Mode:   golearn.ModePicking,
+	}
+
+	state := golearn.State{
+		UserKey: userKey,
+		Question: golearn.Row{
+			Word:      "가다",
+			Translate: "to go",
+		},
+		Answers: []golearn.Row{
+			{Word: "가다", Translate: "to go"},
+			{Word: "오다", Translate: "to come"},
+		},
+		Direction: golearn.DirectionForward,
+	}
+
+	mainMenu := []string{
+		lang["start"],
+		lang["statistics"],
+		lang["settings"],
+		lang["help"],
+	}
+
+	testCases := map[string]struct {
+		Content  string
+		Setup    func(db *mocks.DBService)
+		Code     int
+		Text     string
+		Keyboard keyboard
+	}{
+		"new user is registered": {
+			Content: lang["help"],
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(false, nil)
+				db.On("InsertUser", mock.Anything, user).Return(nil)
+			},
+			Code: http.StatusOK,
+			Text: lang["help_message"],
+			Keyboard: keyboard{
+				Type:    typeButtons,
+				Buttons: mainMenu,
+			},
+		},
+		"failed to register user": {
+			Content: lang["help"],
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(false, nil)
+				db.On("InsertUser", mock.Anything, user).Return(errors.New("sample error"))
+			},
+			Code: http.StatusInternalServerError,
+		},
+		"answer is recorded as activity": {
+			Content: "to go",
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(true, nil)
+				db.On("GetUser", mock.Anything, userKey).Return(user, nil)
+				db.On("GetState", mock.Anything, userKey).Return(state, nil)
+				db.On("InsertActivity", mock.Anything, mock.MatchedBy(func(a golearn.Activity) bool {
+					return a.UserID == userKey && a.Answer == "to go" && a.IsRight && a.State.Question.Word == state.Question.Word
+				})).Return(nil)
+				db.On("GetReview", mock.Anything, userKey, state.Question).Return(golearn.NewReview(userKey, state.Question), nil)
+				db.On("SetReview", mock.Anything, mock.Anything).Return(nil)
+				db.On("SetState", mock.Anything, mock.MatchedBy(func(s golearn.State) bool {
+					return s.UserKey == userKey && s.Answered
+				})).Return(nil)
+			},
+			Code: http.StatusOK,
+			Text: lang["right"],
+			Keyboard: keyboard{
+				Type:    typeButtons,
+				Buttons: []string{lang["next_word"]},
+			},
+		},
+		"typing mode is set": {
+			Content: lang["mode_typing"],
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(true, nil)
+				db.On("GetUser", mock.Anything, userKey).Return(user, nil)
+				db.On("SetUserMode", mock.Anything, userKey, golearn.ModeTyping).Return(nil)
+			},
+			Code: http.StatusOK,
+			Text: lang["mode_set"],
+			Keyboard: keyboard{
+				Type:    typeButtons,
+				Buttons: mainMenu,
+			},
+		},
+		"categories are listed": {
+			Content: lang["categories"],
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(true, nil)
+				db.On("GetUser", mock.Anything, userKey).Return(user, nil)
+				db.On("GetCategories", mock.Anything, userKey).Return([]golearn.Category{
+					{Name: "verbs", Words: 10},
+				}, nil)
+			},
+			Code: http.StatusOK,
+			Text: lang["pick_category"],
+			Keyboard: keyboard{
+				Type: typeButtons,
+				Buttons: []string{
+					lang["categories_icon"] + " verbs",
+					lang["reset_category"],
+					lang["main_menu"],
+				},
+			},
+		},
+		"category is set": {
+			Content: lang["categories_icon"] + " verbs",
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(true, nil)
+				db.On("GetUser", mock.Anything, userKey).Return(user, nil)
+				db.On("SetUserCategory", mock.Anything, userKey, "verbs").Return(nil)
+			},
+			Code: http.StatusOK,
+			Text: lang["category_set"],
+			Keyboard: keyboard{
+				Type:    typeButtons,
+				Buttons: mainMenu,
+			},
+		},
+		"answer is shown": {
+			Content: lang["show_answer"],
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(true, nil)
+				db.On("GetUser", mock.Anything, userKey).Return(user, nil)
+				db.On("GetState", mock.Anything, userKey).Return(state, nil)
+			},
+			Code: http.StatusOK,
+			Text: fmt.Sprintf(lang["right_answer_is"], "to go"),
+			Keyboard: keyboard{
+				Type:    typeButtons,
+				Buttons: []string{lang["next_word"], lang["main_menu"]},
+			},
+		},
+		"statistics is shown": {
+			Content: lang["statistics"],
+			Setup: func(db *mocks.DBService) {
+				db.On("ExistUser", mock.Anything, user).Return(true, nil)
+				db.On("GetUser", mock.Anything, userKey).Return(user, nil)
+				db.On("GetStatistics", mock.Anything, userKey, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(golearn.Statistics{}, nil)
+				db.On("GetBoxes", mock.Anything, userKey).Return([]golearn.Box{}, nil)
+			},
+			Code: http.StatusOK,
+			Text: plainText(lang["statistics_text"] + "\n\n" +
+				lang["statistics_period_today"] + "\n" + fmt.Sprintf(lang["statistics_period_summary"], 0, 0, 0) + "\n" +
+				lang["statistics_period_week"] + "\n" + fmt.Sprintf(lang["statistics_period_summary"], 0, 0, 0) + "\n" +
+				lang["statistics_period_month"] + "\n" + fmt.Sprintf(lang["statistics_period_summary"], 0, 0, 0)),
+			Keyboard: keyboard{
+				Type:    typeButtons,
+				Buttons: mainMenu,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+			tc.Setup(dbService)
+
+			handler := New(Config{
+				Service:         dbService,
+				Lang:            lang,
+				DefaultLanguage: "ru",
+				ColsCount:       2,
+			})
+
+			body := fmt.Sprintf(`{"user_key":%q,"type":"text","content":%q}`, userKey, tc.Content)
+			req := httptest.NewRequest(http.MethodPost, "/kakaobot/message", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.Code, w.Code)
+
+			if tc.Code == http.StatusOK {
+				msg := message{}
+				err := json.Unmarshal(w.Body.Bytes(), &msg)
+
+				assert.Nil(t, err)
+				assert.Equal(t, tc.Text, msg.Message.Text)
+				assert.Equal(t, tc.Keyboard, msg.Keyboard)
+			}
+
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageEndToEnd(t *testing.T) {
+	userKey := "Hg3bR0xq2Ztk"
+
+	db := memory.New(memory.Config{
+		Words: []golearn.Row{
+			{Word: "가다", Translate: "to go", Category: "verbs"},
+			{Word: "오다", Translate: "to come", Category: "verbs"},
+			{Word: "보다", Translate: "to see", Category: "verbs"},
+		},
+	})
+
+	handler := New(Config{
+		Service:         db,
+		Lang:            lang,
+		DefaultLanguage: "ru",
+		ColsCount:       2,
+	})
+
+	send := func(content string) message {
+		body := fmt.Sprintf(`{"user_key":%q,"type":"text","content":%q}`, userKey, content)
+		req := httptest.NewRequest(http.MethodPost, "/kakaobot/message", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d for %q", w.Code, content)
+		}
+
+		msg := message{}
+		err := json.Unmarshal(w.Body.Bytes(), &msg)
+		if err != nil {
+			t.Fatalf("failed to decode response for %q: %v", content, err)
+		}
+
+		return msg
+	}
+
+	send("/start")
+
+	question := send(lang["start"])
+
+	state, err := db.GetState(context.Background(), userKey)
+	assert.Nil(t, err)
+	assert.Equal(t, plainText(state.Ask()), question.Message.Text)
+	assert.Len(t, question.Keyboard.Buttons, len(state.Answers)+1)
+
+	answer := send(state.AnswerOf(state.Question))
+	assert.Equal(t, lang["right"], answer.Message.Text)
+
+	statistics := send(lang["statistics"])
+	assert.Contains(t, statistics.Message.Text, plainText(fmt.Sprintf(lang["statistics_period_summary"], 1, 1, 0)))
+}
+
+func TestMessageWithMatcher(t *testing.T) {
+	userKey := "Hg3bR0xq2Ztk"
+
+	db := memory.New(memory.Config{
+		Words: []golearn.Row{
+			{Word: "학교에 가다", Translate: "to go to school", Category: "verbs"},
+		},
+	})
+
+	handler := New(Config{
+		Service:         db,
+		Lang:            lang,
+		DefaultLanguage: "ru",
+		ColsCount:       2,
+		Matcher:         golearn.NewMatcher(2),
+	})
+
+	send := func(content string) message {
+		body := fmt.Sprintf(`{"user_key":%q,"type":"text","content":%q}`, userKey, content)
+		req := httptest.NewRequest(http.MethodPost, "/kakaobot/message", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		msg := message{}
+		err := json.Unmarshal(w.Body.Bytes(), &msg)
+		if err != nil {
+			t.Fatalf("failed to decode response for %q: %v", content, err)
+		}
+
+		return msg
+	}
+
+	send("/start")
+	send(lang["mode_typing"])
+	send(lang["start"])
+
+	state, err := db.GetState(context.Background(), userKey)
+	assert.Nil(t, err)
+
+	// two typos are more than default distance allows
+	expected := []rune(state.AnswerOf(state.Question))
+	answer := send(string(expected[:len(expected)-2]) + "xx")
+	assert.True(t, strings.HasPrefix(answer.Message.Text, plainText(strings.Split(lang["almost"], "%s")[0])), answer.Message.Text)
+}
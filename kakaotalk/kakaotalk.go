@@ -1,8 +1,23 @@
 package kakaotalk
 
-import "github.com/sergeiten/golearn"
+import (
+	"html"
+	"regexp"
 
-const typeButtons = "buttons"
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/engine"
+)
+
+const (
+	typeButtons = "buttons"
+	typeText    = "text"
+)
+
+// maxBodySize is limit of request body size in bytes.
+const maxBodySize = 1 << 20
+
+// tags matches HTML tags used for formatting of engine replies.
+var tags = regexp.MustCompile(`</?[bi]>`)
 
 type keyboard struct {
 	Type    string   `json:"type"`
@@ -30,4 +45,33 @@ type Config struct {
 	Service         golearn.DBService
 	Lang            golearn.Language
 	DefaultLanguage string
+	// Matcher checks typed answers, engine default is used if it's nil
+	Matcher     golearn.Matcher
+	RecentWords int
+}
+
+// newMessage returns message of engine reply. Kakao shows plain text only
+// and buttons in one list, so formatting is removed and rows are joined.
+// Pressed button sends its text, which is handled by engine as typed one.
+func newMessage(reply engine.Reply) *message {
+	m := &message{}
+	m.Message.Text = plainText(reply.Text)
+	m.Keyboard.Type = typeText
+
+	for _, row := range reply.Keyboard.Rows {
+		for _, b := range row {
+			m.Keyboard.Buttons = append(m.Keyboard.Buttons, b.Text)
+		}
+	}
+
+	if len(m.Keyboard.Buttons) > 0 {
+		m.Keyboard.Type = typeButtons
+	}
+
+	return m
+}
+
+// plainText returns text without formatting tags.
+func plainText(text string) string {
+	return html.UnescapeString(tags.ReplaceAllString(text, ""))
 }
@@ -0,0 +1,169 @@
+package kakaotalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/engine"
+)
+
+// skillVersion is version of skill response format of Kakao i Open Builder.
+const skillVersion = "2.0"
+
+// Limits of skill response, extra buttons and characters are cut.
+const (
+	maxQuickReplies = 10
+	maxLabelLength  = 14
+	maxTextLength   = 1000
+)
+
+// skillRequest is request sent by Kakao i Open Builder to the skill server.
+// Only fields used by the bot are defined.
+type skillRequest struct {
+	UserRequest struct {
+		Utterance string `json:"utterance"`
+		User      struct {
+			ID         string `json:"id"`
+			Properties struct {
+				PlusfriendUserKey string `json:"plusfriendUserKey"`
+			} `json:"properties"`
+		} `json:"user"`
+	} `json:"userRequest"`
+}
+
+type skillResponse struct {
+	Version  string        `json:"version"`
+	Template skillTemplate `json:"template"`
+}
+
+type skillTemplate struct {
+	Outputs      []skillOutput `json:"outputs"`
+	QuickReplies []quickReply  `json:"quickReplies,omitempty"`
+}
+
+type skillOutput struct {
+	SimpleText simpleText `json:"simpleText"`
+}
+
+type simpleText struct {
+	Text string `json:"text"`
+}
+
+// quickReply is button under the reply. Pressed button sends messageText
+// as utterance of the user, label is text of the button.
+type quickReply struct {
+	Label       string `json:"label"`
+	Action      string `json:"action"`
+	MessageText string `json:"messageText"`
+}
+
+// skill handles request of Kakao i Open Builder skill.
+func (h *Handler) skill(w http.ResponseWriter, r *http.Request) {
+	req := &skillRequest{}
+
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(req)
+	if err != nil {
+		golearn.LogPrint(err, "failed to unmarshal skill request")
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	reply, err := h.engine.Handle(r.Context(), req.toUpdate())
+	if err != nil {
+		golearn.LogPrintf(err, "failed to handle utterance %s", req.UserRequest.Utterance)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := json.Marshal(newSkillResponse(reply))
+	if err != nil {
+		golearn.LogPrint(err, "failed to marshal skill response")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err = fmt.Fprint(w, string(resp))
+	golearn.LogPrint(err, "failed to send response")
+}
+
+// toUpdate returns update of skill request. Users are identified by plusfriend user key,
+// which is the same key the retired auto-reply API used, so progress of users is kept.
+// Bot user key is used if channel isn't linked to the bot.
+func (r *skillRequest) toUpdate() *golearn.Update {
+	userID := r.UserRequest.User.Properties.PlusfriendUserKey
+	if userID == "" {
+		userID = r.UserRequest.User.ID
+	}
+
+	return &golearn.Update{
+		ChatID:  userID,
+		UserID:  userID,
+		Message: r.UserRequest.Utterance,
+	}
+}
+
+// newSkillResponse returns skill response of engine reply.
+func newSkillResponse(reply engine.Reply) skillResponse {
+	resp := skillResponse{
+		Version: skillVersion,
+		Template: skillTemplate{
+			Outputs: []skillOutput{
+				{
+					SimpleText: simpleText{
+						Text: truncate(plainText(reply.Text), maxTextLength),
+					},
+				},
+			},
+		},
+	}
+
+	for _, b := range quickButtons(reply.Keyboard.Rows) {
+		resp.Template.QuickReplies = append(resp.Template.QuickReplies, quickReply{
+			Label:       truncate(b.Text, maxLabelLength),
+			Action:      "message",
+			MessageText: b.Text,
+		})
+	}
+
+	return resp
+}
+
+// quickButtons returns buttons of rows joined in one list cut to maxQuickReplies.
+// The last row keeps navigation, e.g. main menu, so buttons before it are cut first.
+// Engine pages long menus, so buttons are cut only if some menu isn't paged.
+func quickButtons(rows [][]engine.Button) []engine.Button {
+	var all []engine.Button
+	for _, row := range rows {
+		all = append(all, row...)
+	}
+
+	if len(all) <= maxQuickReplies {
+		return all
+	}
+
+	log.Printf("kakaotalk quick replies are cut to %d, %d buttons are dropped", maxQuickReplies, len(all)-maxQuickReplies)
+
+	last := rows[len(rows)-1]
+	if len(last) >= maxQuickReplies {
+		return last[:maxQuickReplies]
+	}
+
+	keep := maxQuickReplies - len(last)
+
+	return append(all[:keep:keep], last...)
+}
+
+// truncate returns text cut to passed number of characters.
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit])
+}
@@ -0,0 +1,322 @@
+package kakaotalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/engine"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var lang golearn.Language
+
+func init() {
+	langFilename := fmt.Sprintf("../lang.%s.json", "ru")
+	langContent, err := ioutil.ReadFile(langFilename)
+	golearn.LogFatal(err, "failed to get language file content")
+
+	lang, err = golearn.GetLanguage(langContent)
+	golearn.LogFatal(err, "failed to create language instance")
+}
+
+func TestSkillRequestToUpdate(t *testing.T) {
+	testCases := map[string]struct {
+		File     string
+		Expected *golearn.Update
+	}{
+		"user of channel": {
+			File: "testdata/skill_request.json",
+			Expected: &golearn.Update{
+				ChatID:  "Hg3bR0xq2Ztk",
+				UserID:  "Hg3bR0xq2Ztk",
+				Message: "/start",
+			},
+		},
+		"user of bot only": {
+			File: "testdata/skill_request_bot_user.json",
+			Expected: &golearn.Update{
+				ChatID:  "0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a",
+				UserID:  "0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a",
+				Message: "가다",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			content, err := ioutil.ReadFile(tc.File)
+			if err != nil {
+				t.Fatal("failed to read fixture")
+			}
+
+			req := &skillRequest{}
+			err = json.Unmarshal(content, req)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Expected, req.toUpdate())
+		})
+	}
+}
+
+func TestServeHTTPSkill(t *testing.T) {
+	dbService := &mocks.DBService{}
+
+	handler := New(Config{
+		Service:         dbService,
+		Lang:            lang,
+		DefaultLanguage: "ru",
+		ColsCount:       2,
+	})
+
+	user := golearn.User{
+		UserID: "Hg3bR0xq2Ztk",
+		Mode:   golearn.ModePicking,
+	}
+
+	dbService.On("ExistUser", mock.Anything, user).Return(true, nil)
+	dbService.On("GetUser", mock.Anything, user.UserID).Return(user, nil)
+
+	body, err := os.Open("testdata/skill_request.json")
+	if err != nil {
+		t.Fatal("failed to open fixture")
+	}
+	defer body.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/kakaobot/skill", body)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	resp := skillResponse{}
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	assert.Nil(t, err)
+	assert.Equal(t, skillResponse{
+		Version: "2.0",
+		Template: skillTemplate{
+			Outputs: []skillOutput{
+				{SimpleText: simpleText{Text: lang["welcome"]}},
+			},
+			QuickReplies: []quickReply{
+				{Label: lang["start"], Action: "message", MessageText: lang["start"]},
+				{Label: lang["statistics"], Action: "message", MessageText: lang["statistics"]},
+				{Label: lang["settings"], Action: "message", MessageText: lang["settings"]},
+				{Label: lang["help"], Action: "message", MessageText: lang["help"]},
+			},
+		},
+	}, resp)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestServeHTTPSkillWithManyCategories(t *testing.T) {
+	dbService := &mocks.DBService{}
+
+	handler := New(Config{
+		Service:         dbService,
+		Lang:            lang,
+		DefaultLanguage: "ru",
+		ColsCount:       2,
+	})
+
+	user := golearn.User{
+		UserID: "Hg3bR0xq2Ztk",
+		Mode:   golearn.ModePicking,
+	}
+
+	var categories []golearn.Category
+	for i := 1; i <= 12; i++ {
+		categories = append(categories, golearn.Category{Name: fmt.Sprintf("2019-01-%02d", i), Words: 10})
+	}
+
+	dbService.On("ExistUser", mock.Anything, user).Return(true, nil)
+	dbService.On("GetUser", mock.Anything, user.UserID).Return(user, nil)
+	dbService.On("GetCategories", mock.Anything, user.UserID).Return(categories, nil)
+
+	send := func(utterance string) []string {
+		req := skillRequest{}
+		req.UserRequest.Utterance = utterance
+		req.UserRequest.User.Properties.PlusfriendUserKey = user.UserID
+
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal("failed to marshal request")
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/kakaobot/skill", strings.NewReader(string(body))))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		resp := skillResponse{}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, len(resp.Template.QuickReplies), maxQuickReplies)
+
+		var texts []string
+		for _, r := range resp.Template.QuickReplies {
+			texts = append(texts, r.MessageText)
+		}
+
+		assert.Contains(t, texts, lang["main_menu"])
+
+		return texts
+	}
+
+	picked := map[string]bool{}
+	pages := map[string]string{
+		lang["categories"]:                      fmt.Sprintf(lang["categories_more"], 2),
+		fmt.Sprintf(lang["categories_more"], 2): fmt.Sprintf(lang["categories_more"], 1),
+	}
+	for utterance, more := range pages {
+		texts := send(utterance)
+		assert.Contains(t, texts, more)
+
+		for _, text := range texts {
+			if strings.HasPrefix(text, lang["categories_icon"]) {
+				picked[text] = true
+			}
+		}
+	}
+
+	assert.Len(t, picked, len(categories))
+	for _, c := range categories {
+		assert.True(t, picked[lang["categories_icon"]+" "+c.Name], c.Name)
+	}
+
+	dbService.AssertExpectations(t)
+}
+
+func TestServeHTTPSkillWithInvalidRequest(t *testing.T) {
+	handler := New(Config{
+		Service: &mocks.DBService{},
+		Lang:    lang,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/kakaobot/skill", strings.NewReader("{"))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNewSkillResponse(t *testing.T) {
+	var many []engine.Button
+	for i := 0; i < 12; i++ {
+		many = append(many, engine.Button{Text: "option " + strconv.Itoa(i)})
+	}
+
+	testCases := map[string]struct {
+		Reply    engine.Reply
+		Expected skillResponse
+	}{
+		"formatting is removed": {
+			Reply: engine.Reply{
+				Text: "<b>가다</b> добавлено в коллекцию <b>&lt;verbs&gt;</b>",
+			},
+			Expected: skillResponse{
+				Version: "2.0",
+				Template: skillTemplate{
+					Outputs: []skillOutput{
+						{SimpleText: simpleText{Text: "가다 добавлено в коллекцию <verbs>"}},
+					},
+				},
+			},
+		},
+		"rows are joined and long label is cut": {
+			Reply: engine.Reply{
+				Text: "가다",
+				Keyboard: engine.Keyboard{
+					Rows: [][]engine.Button{
+						{{Text: "to go", Data: "answer:5c6e5a1b:0"}, {Text: "to go somewhere far away", Data: "answer:5c6e5a1b:1"}},
+						{{Text: lang["main_menu"], Data: lang["main_menu"]}},
+					},
+					Inline: true,
+				},
+			},
+			Expected: skillResponse{
+				Version: "2.0",
+				Template: skillTemplate{
+					Outputs: []skillOutput{
+						{SimpleText: simpleText{Text: "가다"}},
+					},
+					QuickReplies: []quickReply{
+						{Label: "to go", Action: "message", MessageText: "to go"},
+						{Label: "to go somewher", Action: "message", MessageText: "to go somewhere far away"},
+						{Label: lang["main_menu"], Action: "message", MessageText: lang["main_menu"]},
+					},
+				},
+			},
+		},
+		"extra buttons are cut before the last row": {
+			Reply: engine.Reply{
+				Text: "categories",
+				Keyboard: engine.Keyboard{
+					Rows: [][]engine.Button{
+						many[:6],
+						many[6:],
+						{{Text: lang["main_menu"]}},
+					},
+				},
+			},
+			Expected: skillResponse{
+				Version: "2.0",
+				Template: skillTemplate{
+					Outputs: []skillOutput{
+						{SimpleText: simpleText{Text: "categories"}},
+					},
+					QuickReplies: func() []quickReply {
+						var replies []quickReply
+						for _, b := range append(many[:9:9], engine.Button{Text: lang["main_menu"]}) {
+							replies = append(replies, quickReply{Label: truncate(b.Text, maxLabelLength), Action: "message", MessageText: b.Text})
+						}
+						return replies
+					}(),
+				},
+			},
+		},
+		"extra buttons are cut": {
+			Reply: engine.Reply{
+				Text: "categories",
+				Keyboard: engine.Keyboard{
+					Rows: [][]engine.Button{many},
+				},
+			},
+			Expected: skillResponse{
+				Version: "2.0",
+				Template: skillTemplate{
+					Outputs: []skillOutput{
+						{SimpleText: simpleText{Text: "categories"}},
+					},
+					QuickReplies: func() []quickReply {
+						var replies []quickReply
+						for _, b := range many[:10] {
+							replies = append(replies, quickReply{Label: b.Text, Action: "message", MessageText: b.Text})
+						}
+						return replies
+					}(),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, newSkillResponse(tc.Reply))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package golearn
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultMaxDistance is maximum edit distance of close answer used when it's not configured.
+const DefaultMaxDistance = 1
+
+// Verdict represents result of checking user answer.
+type Verdict string
+
+// VerdictExact constant for answer equal to the expected one
+const VerdictExact Verdict = "exact"
+
+// VerdictClose constant for answer with a few typos
+const VerdictClose Verdict = "close"
+
+// VerdictWrong constant for wrong answer
+const VerdictWrong Verdict = "wrong"
+
+// Matcher checks user answer against expected one.
+type Matcher interface {
+	Match(expected string, answer string) Verdict
+}
+
+// ExactMatcher accepts only answers equal to the expected one.
+// Used for answers picked from keyboard, which are always sent as is.
+type ExactMatcher struct{}
+
+// Match returns exact verdict if answer equals to expected one, otherwise wrong verdict.
+func (m ExactMatcher) Match(expected string, answer string) Verdict {
+	if expected == answer {
+		return VerdictExact
+	}
+	return VerdictWrong
+}
+
+// TolerantMatcher accepts answers which differ from expected one by case, spacing,
+// punctuation and Unicode normalization form. Answers within MaxDistance edits
+// from expected one are considered as close.
+type TolerantMatcher struct {
+	MaxDistance int
+}
+
+// NewMatcher returns tolerant matcher with passed maximum edit distance of close answers.
+func NewMatcher(maxDistance int) *TolerantMatcher {
+	return &TolerantMatcher{
+		MaxDistance: maxDistance,
+	}
+}
+
+// Match compares normalized expected and user answers.
+// Answer is close only if it isn't completely different from expected one,
+// so one letter words with a typo are wrong.
+func (m *TolerantMatcher) Match(expected string, answer string) Verdict {
+	e := Normalize(expected)
+	a := Normalize(answer)
+
+	if e == a {
+		return VerdictExact
+	}
+
+	distance := Distance(e, a)
+	if distance <= m.MaxDistance && distance < len([]rune(e)) {
+		return VerdictClose
+	}
+
+	return VerdictWrong
+}
+
+// Normalize returns string converted to NFC form and lower case
+// without punctuation and with single spaces between words.
+func Normalize(s string) string {
+	s = norm.NFC.String(s)
+	s = strings.ToLower(s)
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Distance returns Levenshtein distance between passed strings in runes.
+func Distance(a string, b string) int {
+	ar := []rune(a)
+	br := []rune(b)
+
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(br)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
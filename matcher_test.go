@@ -0,0 +1,116 @@
+package golearn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTolerantMatcher(t *testing.T) {
+	testCases := map[string]struct {
+		MaxDistance int
+		Expected    string
+		Answer      string
+		Verdict     Verdict
+	}{
+		"equal answer": {
+			MaxDistance: 1,
+			Expected:    "идти",
+			Answer:      "идти",
+			Verdict:     VerdictExact,
+		},
+		"trailing space": {
+			MaxDistance: 0,
+			Expected:    "идти",
+			Answer:      "идти ",
+			Verdict:     VerdictExact,
+		},
+		"different case and inner spaces": {
+			MaxDistance: 0,
+			Expected:    "Good morning",
+			Answer:      "good   MORNING",
+			Verdict:     VerdictExact,
+		},
+		"punctuation": {
+			MaxDistance: 0,
+			Expected:    "안녕하세요!",
+			Answer:      "안녕하세요",
+			Verdict:     VerdictExact,
+		},
+		"hangul in NFD form": {
+			MaxDistance: 0,
+			Expected:    "한글",
+			Answer:      "\u1112\u1161\u11ab\u1100\u1173\u11af",
+			Verdict:     VerdictExact,
+		},
+		"one typo": {
+			MaxDistance: 1,
+			Expected:    "ходить",
+			Answer:      "хадить",
+			Verdict:     VerdictClose,
+		},
+		"missed letter": {
+			MaxDistance: 1,
+			Expected:    "ходить",
+			Answer:      "ходит",
+			Verdict:     VerdictClose,
+		},
+		"typo is not allowed": {
+			MaxDistance: 0,
+			Expected:    "ходить",
+			Answer:      "хадить",
+			Verdict:     VerdictWrong,
+		},
+		"two typos": {
+			MaxDistance: 1,
+			Expected:    "ходить",
+			Answer:      "хадитя",
+			Verdict:     VerdictWrong,
+		},
+		"one syllable word with typo": {
+			MaxDistance: 1,
+			Expected:    "책",
+			Answer:      "잭",
+			Verdict:     VerdictWrong,
+		},
+		"empty answer": {
+			MaxDistance: 1,
+			Expected:    "책",
+			Answer:      "",
+			Verdict:     VerdictWrong,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			matcher := NewMatcher(tc.MaxDistance)
+
+			assert.Equal(t, tc.Verdict, matcher.Match(tc.Expected, tc.Answer))
+		})
+	}
+}
+
+func TestExactMatcher(t *testing.T) {
+	matcher := ExactMatcher{}
+
+	assert.Equal(t, VerdictExact, matcher.Match("идти", "идти"))
+	assert.Equal(t, VerdictWrong, matcher.Match("идти", "идти "))
+}
+
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		A        string
+		B        string
+		Expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"한국어", "한국", 1},
+		{"사과", "사관", 1},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.Expected, Distance(tc.A, tc.B), "%s and %s", tc.A, tc.B)
+	}
+}
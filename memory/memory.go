@@ -0,0 +1,579 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sergeiten/golearn"
+)
+
+// ErrNotFound is returned if requested word, user or state doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Config of in-memory database.
+type Config struct {
+	// Rand is used to pick random words, new source seeded by current time is used if it's nil
+	Rand *rand.Rand
+	// Words are inserted on creation
+	Words []golearn.Row
+}
+
+// Service keeps data in memory, it's safe for concurrent use.
+// Data is lost when program exits, so it's useful for tests and demos.
+type Service struct {
+	mu         sync.Mutex
+	rnd        *rand.Rand
+	lastID     int
+	words      []golearn.Row
+	users      map[string]golearn.User
+	states     map[string][]golearn.State
+	activities []golearn.Activity
+	reviews    map[reviewKey]golearn.Review
+}
+
+type reviewKey struct {
+	userID   string
+	word     string
+	category string
+}
+
+// New returns new instance of Service.
+func New(cfg Config) *Service {
+	rnd := cfg.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	s := &Service{
+		rnd:     rnd,
+		users:   map[string]golearn.User{},
+		states:  map[string][]golearn.State{},
+		reviews: map[reviewKey]golearn.Review{},
+	}
+
+	for _, w := range cfg.Words {
+		s.insertWord(w)
+	}
+
+	return s
+}
+
+// Close does nothing, it's implemented to satisfy golearn.DBService.
+func (s *Service) Close() {}
+
+// RandomQuestion returns random row of global words and words of passed user.
+// Words in exclude are skipped unless there is no other word.
+func (s *Service) RandomQuestion(ctx context.Context, userID string, category string, exclude []string) (golearn.Row, error) {
+	if err := s.lock(ctx); err != nil {
+		return golearn.Row{}, err
+	}
+	defer s.mu.Unlock()
+
+	return golearn.ExcludeRecent(exclude, func(exclude []string) (golearn.Row, error) {
+		skip := wordSet(exclude)
+		rows := s.filterWords(func(w golearn.Row) bool {
+			return visibleTo(w, userID) && inCategory(w, category) && !skip[w.Word]
+		})
+
+		return s.pick(rows)
+	})
+}
+
+// NextDueQuestion returns the word user has to review next.
+// Word with the most overdue review goes first. If there is nothing to review
+// random word which user has never seen is returned. If user has seen all words
+// the word with the closest review date is returned.
+// Words in exclude are skipped unless there is no other word.
+func (s *Service) NextDueQuestion(ctx context.Context, userID string, category string, now time.Time, exclude []string) (golearn.Row, error) {
+	if err := s.lock(ctx); err != nil {
+		return golearn.Row{}, err
+	}
+	defer s.mu.Unlock()
+
+	return golearn.ExcludeRecent(exclude, func(exclude []string) (golearn.Row, error) {
+		return s.nextDueQuestion(userID, category, now, wordSet(exclude))
+	})
+}
+
+func (s *Service) nextDueQuestion(userID string, category string, now time.Time, skip map[string]bool) (golearn.Row, error) {
+	var reviews []golearn.Review
+	seen := map[string]bool{}
+	for key, review := range s.reviews {
+		if key.userID != userID {
+			continue
+		}
+		seen[key.word] = true
+		if (category == "" || key.category == category) && !skip[key.word] && s.hasWord(review) {
+			reviews = append(reviews, review)
+		}
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].Due.Before(reviews[j].Due)
+	})
+
+	if len(reviews) > 0 && !reviews[0].Due.After(now) {
+		return s.reviewRow(reviews[0])
+	}
+
+	unseen := s.filterWords(func(w golearn.Row) bool {
+		return visibleTo(w, userID) && inCategory(w, category) && !seen[w.Word] && !skip[w.Word]
+	})
+	if len(unseen) > 0 {
+		return s.pick(unseen)
+	}
+
+	if len(reviews) == 0 {
+		return golearn.Row{}, golearn.ErrNoWords
+	}
+
+	return s.reviewRow(reviews[0])
+}
+
+// hasWord reports if word of the review exists, reviews of deleted words are skipped.
+func (s *Service) hasWord(review golearn.Review) bool {
+	_, err := s.reviewRow(review)
+	return err == nil
+}
+
+func (s *Service) reviewRow(review golearn.Review) (golearn.Row, error) {
+	for _, w := range s.words {
+		if w.Word == review.Word && w.Category == review.Category && visibleTo(w, review.UserID) {
+			return w, nil
+		}
+	}
+
+	return golearn.Row{}, ErrNotFound
+}
+
+// RandomAnswers returns random answers with passed count of total answers.
+// q is right answer which is appended to result slice and will be shuffled later.
+// Wrong answers are picked by golearn.PickDistractors.
+func (s *Service) RandomAnswers(ctx context.Context, userID string, q golearn.Row, count int) ([]golearn.Row, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	r, err := golearn.PickDistractors(q, count-1, func(sameCategory bool, limit int) ([]golearn.Row, error) {
+		rows := s.filterWords(func(w golearn.Row) bool {
+			return visibleTo(w, userID) && w.Word != q.Word && (w.Category == q.Category) == sameCategory
+		})
+
+		var sample []golearn.Row
+		for _, i := range s.rnd.Perm(len(rows)) {
+			if len(sample) >= limit {
+				break
+			}
+			sample = append(sample, rows[i])
+		}
+
+		return sample, nil
+	})
+	if err != nil {
+		return r, err
+	}
+
+	return append(r, q), nil
+}
+
+// SetState save latest given set of question and answers
+func (s *Service) SetState(ctx context.Context, state golearn.State) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.states[state.UserKey] = append(s.states[state.UserKey], state)
+
+	return nil
+}
+
+// GetState returns lastest saved user state
+func (s *Service) GetState(ctx context.Context, userKey string) (golearn.State, error) {
+	if err := s.lock(ctx); err != nil {
+		return golearn.State{}, err
+	}
+	defer s.mu.Unlock()
+
+	states := s.states[userKey]
+	if len(states) == 0 {
+		return golearn.State{}, ErrNotFound
+	}
+
+	latest := states[0]
+	for _, state := range states[1:] {
+		if state.Timestamp >= latest.Timestamp {
+			latest = state
+		}
+	}
+
+	return latest, nil
+}
+
+// RecentWords returns words asked in last limit states of user, the latest first.
+func (s *Service) RecentWords(ctx context.Context, userKey string, limit int) ([]string, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	// states are saved in order, so the last one goes first if timestamps are equal
+	var states []golearn.State
+	for i := len(s.states[userKey]) - 1; i >= 0; i-- {
+		states = append(states, s.states[userKey][i])
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].Timestamp > states[j].Timestamp
+	})
+
+	if len(states) > limit {
+		states = states[:limit]
+	}
+
+	return golearn.StateWords(states), nil
+}
+
+// ResetState resets user state
+func (s *Service) ResetState(ctx context.Context, userKey string) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	delete(s.states, userKey)
+
+	return nil
+}
+
+// InsertWord inserts new word
+func (s *Service) InsertWord(ctx context.Context, w golearn.Row) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.insertWord(w)
+
+	return nil
+}
+
+func (s *Service) insertWord(w golearn.Row) {
+	s.lastID++
+	w.ID = s.lastID
+	s.words = append(s.words, w)
+}
+
+// InsertUser inserts new user
+func (s *Service) InsertUser(ctx context.Context, user golearn.User) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.users[user.UserID] = user
+
+	return nil
+}
+
+// UpdateUser updates user
+func (s *Service) UpdateUser(ctx context.Context, user golearn.User) error {
+	return s.updateUser(ctx, user.UserID, func(u *golearn.User) {
+		*u = user
+	})
+}
+
+// ExistUser returns bool if user already exists
+func (s *Service) ExistUser(ctx context.Context, user golearn.User) (bool, error) {
+	if user.UserID == "" {
+		return false, errors.New("passed user has empty id")
+	}
+
+	if err := s.lock(ctx); err != nil {
+		return false, err
+	}
+	defer s.mu.Unlock()
+
+	_, ok := s.users[user.UserID]
+
+	return ok, nil
+}
+
+// GetUser returns user
+func (s *Service) GetUser(ctx context.Context, userID string) (golearn.User, error) {
+	if userID == "" {
+		return golearn.User{}, errors.New("passed user id is empty")
+	}
+
+	if err := s.lock(ctx); err != nil {
+		return golearn.User{}, err
+	}
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return user, ErrNotFound
+	}
+
+	return user, nil
+}
+
+// SetUserMode sets new mode for passed user id
+func (s *Service) SetUserMode(ctx context.Context, userID string, mode string) error {
+	return s.updateUser(ctx, userID, func(u *golearn.User) {
+		u.Mode = mode
+	})
+}
+
+// SetUserDirection sets new direction of questions for passed user id
+func (s *Service) SetUserDirection(ctx context.Context, userID string, direction string) error {
+	return s.updateUser(ctx, userID, func(u *golearn.User) {
+		u.Direction = direction
+	})
+}
+
+// SetUserLatinInput enables or disables conversion of answers typed on Latin keyboard for passed user id
+func (s *Service) SetUserLatinInput(ctx context.Context, userID string, enabled bool) error {
+	return s.updateUser(ctx, userID, func(u *golearn.User) {
+		u.LatinInput = enabled
+	})
+}
+
+// SetUserAnswers sets count of answers in picking mode for passed user id
+func (s *Service) SetUserAnswers(ctx context.Context, userID string, count int) error {
+	return s.updateUser(ctx, userID, func(u *golearn.User) {
+		u.Answers = count
+	})
+}
+
+// SetUserCategory sets category of questions for passed user id
+func (s *Service) SetUserCategory(ctx context.Context, userID string, category string) error {
+	return s.updateUser(ctx, userID, func(u *golearn.User) {
+		u.Category = category
+	})
+}
+
+func (s *Service) updateUser(ctx context.Context, userID string, update func(u *golearn.User)) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	update(&user)
+	s.users[userID] = user
+
+	return nil
+}
+
+// GetCategories returns list of unique categories of words sorted by name.
+// Global categories are returned along with own collections of passed user.
+func (s *Service) GetCategories(ctx context.Context, userID string) ([]golearn.Category, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, w := range s.words {
+		if w.Category != "" && visibleTo(w, userID) {
+			counts[w.Category]++
+		}
+	}
+
+	var categories []golearn.Category
+	for name, words := range counts {
+		categories = append(categories, golearn.Category{Name: name, Words: words})
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories, nil
+}
+
+// DeleteWordsByCategory removes words of category owned by passed user.
+// Empty user id removes global words only.
+func (s *Service) DeleteWordsByCategory(ctx context.Context, userID string, category string) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	words := s.words[:0]
+	for _, w := range s.words {
+		if w.Category != category || w.Owner != userID {
+			words = append(words, w)
+		}
+	}
+	s.words = words
+
+	return nil
+}
+
+// InsertActivity saves user answer.
+func (s *Service) InsertActivity(ctx context.Context, activity golearn.Activity) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.activities = append(s.activities, activity)
+
+	return nil
+}
+
+// GetStatistics returns count of right and wrong answers of user for passed day, ISO week and month.
+// Week is counted in ISO year of the day. Dates of answers are compared in UTC.
+func (s *Service) GetStatistics(ctx context.Context, userID string, year int, month int, week int, day int) (golearn.Statistics, error) {
+	if err := s.lock(ctx); err != nil {
+		return golearn.Statistics{}, err
+	}
+	defer s.mu.Unlock()
+
+	var statistics golearn.Statistics
+	weekYear := golearn.WeekYear(year, month, day)
+
+	for _, a := range s.activities {
+		if a.UserID != userID {
+			continue
+		}
+
+		timestamp := a.Timestamp.UTC()
+		y, m, d := timestamp.Date()
+		wy, w := timestamp.ISOWeek()
+
+		if y == year && int(m) == month && d == day {
+			count(&statistics.Today, a.IsRight)
+		}
+		if wy == weekYear && w == week {
+			count(&statistics.Week, a.IsRight)
+		}
+		if y == year && int(m) == month {
+			count(&statistics.Month, a.IsRight)
+		}
+	}
+
+	return statistics, nil
+}
+
+func count(row *golearn.StatRow, isRight bool) {
+	row.Total++
+	if isRight {
+		row.Right++
+	} else {
+		row.Wrong++
+	}
+}
+
+// GetReview returns user review of passed row.
+// New review is returned if user has never reviewed the row.
+func (s *Service) GetReview(ctx context.Context, userID string, row golearn.Row) (golearn.Review, error) {
+	if err := s.lock(ctx); err != nil {
+		return golearn.Review{}, err
+	}
+	defer s.mu.Unlock()
+
+	review, ok := s.reviews[reviewKey{userID, row.Word, row.Category}]
+	if !ok {
+		return golearn.NewReview(userID, row), nil
+	}
+
+	return review, nil
+}
+
+// SetReview inserts or updates user review of the word.
+func (s *Service) SetReview(ctx context.Context, review golearn.Review) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.reviews[reviewKey{review.UserID, review.Word, review.Category}] = review
+
+	return nil
+}
+
+// GetBoxes returns Leitner boxes of user with count of words in each box.
+// Empty boxes are not returned.
+func (s *Service) GetBoxes(ctx context.Context, userID string) ([]golearn.Box, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	counts := map[int]int{}
+	for key, review := range s.reviews {
+		if key.userID == userID && review.Box > 0 {
+			counts[review.Box]++
+		}
+	}
+
+	var boxes []golearn.Box
+	for number, words := range counts {
+		boxes = append(boxes, golearn.Box{Number: number, Words: words})
+	}
+
+	sort.Slice(boxes, func(i, j int) bool {
+		return boxes[i].Number < boxes[j].Number
+	})
+
+	return boxes, nil
+}
+
+// lock locks service if ctx isn't done yet.
+func (s *Service) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+
+	return nil
+}
+
+func (s *Service) filterWords(match func(w golearn.Row) bool) []golearn.Row {
+	var rows []golearn.Row
+	for _, w := range s.words {
+		if match(w) {
+			rows = append(rows, w)
+		}
+	}
+
+	return rows
+}
+
+func (s *Service) pick(rows []golearn.Row) (golearn.Row, error) {
+	if len(rows) == 0 {
+		return golearn.Row{}, golearn.ErrNoWords
+	}
+
+	return rows[s.rnd.Intn(len(rows))], nil
+}
+
+// visibleTo reports if word is global or owned by passed user.
+func visibleTo(w golearn.Row, userID string) bool {
+	return w.Owner == "" || w.Owner == userID
+}
+
+func inCategory(w golearn.Row, category string) bool {
+	return category == "" || w.Category == category
+}
+
+func wordSet(words []string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range words {
+		set[w] = true
+	}
+
+	return set
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/dbtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) golearn.DBService {
+		return New(Config{})
+	})
+}
+
+func TestRandomQuestionWithRand(t *testing.T) {
+	var words []golearn.Row
+	for i := 0; i < 20; i++ {
+		words = append(words, golearn.Row{Word: "word " + strconv.Itoa(i), Translate: "translate " + strconv.Itoa(i)})
+	}
+
+	ctx := context.Background()
+
+	questions := func() []string {
+		s := New(Config{
+			Rand:  rand.New(rand.NewSource(42)),
+			Words: words,
+		})
+
+		var picked []string
+		for i := 0; i < 10; i++ {
+			row, err := s.RandomQuestion(ctx, "177374215", "", nil)
+			assert.Nil(t, err)
+			picked = append(picked, row.Word)
+		}
+		return picked
+	}
+
+	assert.Equal(t, questions(), questions())
+}
+
+func TestRandomQuestionWithoutWords(t *testing.T) {
+	ctx := context.Background()
+	s := New(Config{})
+
+	_, err := s.RandomQuestion(ctx, "177374215", "", nil)
+	assert.Equal(t, golearn.ErrNoWords, err)
+
+	_, err = s.NextDueQuestion(ctx, "177374215", "", time.Now(), nil)
+	assert.Equal(t, golearn.ErrNoWords, err)
+}
+
+func TestConcurrentUse(t *testing.T) {
+	ctx := context.Background()
+	s := New(Config{
+		Words: []golearn.Row{
+			{Word: "가다", Translate: "to go"},
+			{Word: "오다", Translate: "to come"},
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			userID := strconv.Itoa(i)
+			assert.Nil(t, s.InsertUser(ctx, golearn.User{UserID: userID}))
+
+			row, err := s.RandomQuestion(ctx, userID, "", nil)
+			assert.Nil(t, err)
+
+			answers, err := s.RandomAnswers(ctx, userID, row, 4)
+			assert.Nil(t, err)
+			assert.Len(t, answers, 2)
+
+			assert.Nil(t, s.SetState(ctx, golearn.State{UserKey: userID, Question: row, Answers: answers}))
+			assert.Nil(t, s.InsertActivity(ctx, golearn.Activity{UserID: userID, IsRight: true, Timestamp: time.Now()}))
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 50; i++ {
+		state, err := s.GetState(ctx, strconv.Itoa(i))
+		assert.Nil(t, err)
+		assert.Len(t, state.Answers, 2)
+	}
+}
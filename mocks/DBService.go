@@ -2,8 +2,10 @@
 
 package mocks
 
+import context "context"
 import golearn "github.com/sergeiten/golearn"
 import mock "github.com/stretchr/testify/mock"
+import time "time"
 
 // DBService is an autogenerated mock type for the DBService type
 type DBService struct {
@@ -15,13 +17,13 @@ func (_m *DBService) Close() {
 	_m.Called()
 }
 
-// DeleteWordsByCategory provides a mock function with given fields: userID, category
-func (_m *DBService) DeleteWordsByCategory(userID string, category string) error {
-	ret := _m.Called(userID, category)
+// DeleteWordsByCategory provides a mock function with given fields: ctx, userID, category
+func (_m *DBService) DeleteWordsByCategory(ctx context.Context, userID string, category string) error {
+	ret := _m.Called(ctx, userID, category)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(userID, category)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, category)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -29,20 +31,20 @@ func (_m *DBService) DeleteWordsByCategory(userID string, category string) error
 	return r0
 }
 
-// ExistUser provides a mock function with given fields: user
-func (_m *DBService) ExistUser(user golearn.User) (bool, error) {
-	ret := _m.Called(user)
+// ExistUser provides a mock function with given fields: ctx, user
+func (_m *DBService) ExistUser(ctx context.Context, user golearn.User) (bool, error) {
+	ret := _m.Called(ctx, user)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(golearn.User) bool); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, golearn.User) bool); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(golearn.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, golearn.User) error); ok {
+		r1 = rf(ctx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -50,13 +52,36 @@ func (_m *DBService) ExistUser(user golearn.User) (bool, error) {
 	return r0, r1
 }
 
-// GetCategories provides a mock function with given fields: userID
-func (_m *DBService) GetCategories(userID string) ([]golearn.Category, error) {
-	ret := _m.Called(userID)
+// GetBoxes provides a mock function with given fields: ctx, userID
+func (_m *DBService) GetBoxes(ctx context.Context, userID string) ([]golearn.Box, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []golearn.Box
+	if rf, ok := ret.Get(0).(func(context.Context, string) []golearn.Box); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]golearn.Box)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCategories provides a mock function with given fields: ctx, userID
+func (_m *DBService) GetCategories(ctx context.Context, userID string) ([]golearn.Category, error) {
+	ret := _m.Called(ctx, userID)
 
 	var r0 []golearn.Category
-	if rf, ok := ret.Get(0).(func(string) []golearn.Category); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []golearn.Category); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]golearn.Category)
@@ -64,8 +89,8 @@ func (_m *DBService) GetCategories(userID string) ([]golearn.Category, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -73,20 +98,41 @@ func (_m *DBService) GetCategories(userID string) ([]golearn.Category, error) {
 	return r0, r1
 }
 
-// GetState provides a mock function with given fields: _a0
-func (_m *DBService) GetState(_a0 string) (golearn.State, error) {
-	ret := _m.Called(_a0)
+// GetReview provides a mock function with given fields: ctx, userID, row
+func (_m *DBService) GetReview(ctx context.Context, userID string, row golearn.Row) (golearn.Review, error) {
+	ret := _m.Called(ctx, userID, row)
+
+	var r0 golearn.Review
+	if rf, ok := ret.Get(0).(func(context.Context, string, golearn.Row) golearn.Review); ok {
+		r0 = rf(ctx, userID, row)
+	} else {
+		r0 = ret.Get(0).(golearn.Review)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, golearn.Row) error); ok {
+		r1 = rf(ctx, userID, row)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetState provides a mock function with given fields: ctx, userKey
+func (_m *DBService) GetState(ctx context.Context, userKey string) (golearn.State, error) {
+	ret := _m.Called(ctx, userKey)
 
 	var r0 golearn.State
-	if rf, ok := ret.Get(0).(func(string) golearn.State); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string) golearn.State); ok {
+		r0 = rf(ctx, userKey)
 	} else {
 		r0 = ret.Get(0).(golearn.State)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userKey)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -94,20 +140,20 @@ func (_m *DBService) GetState(_a0 string) (golearn.State, error) {
 	return r0, r1
 }
 
-// GetStatistics provides a mock function with given fields: userID, year, month, week, day
-func (_m *DBService) GetStatistics(userID string, year int, month int, week int, day int) (golearn.Statistics, error) {
-	ret := _m.Called(userID, year, month, week, day)
+// GetStatistics provides a mock function with given fields: ctx, userID, year, month, week, day
+func (_m *DBService) GetStatistics(ctx context.Context, userID string, year int, month int, week int, day int) (golearn.Statistics, error) {
+	ret := _m.Called(ctx, userID, year, month, week, day)
 
 	var r0 golearn.Statistics
-	if rf, ok := ret.Get(0).(func(string, int, int, int, int) golearn.Statistics); ok {
-		r0 = rf(userID, year, month, week, day)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, int, int) golearn.Statistics); ok {
+		r0 = rf(ctx, userID, year, month, week, day)
 	} else {
 		r0 = ret.Get(0).(golearn.Statistics)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int, int, int, int) error); ok {
-		r1 = rf(userID, year, month, week, day)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int, int, int) error); ok {
+		r1 = rf(ctx, userID, year, month, week, day)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -115,20 +161,20 @@ func (_m *DBService) GetStatistics(userID string, year int, month int, week int,
 	return r0, r1
 }
 
-// GetUser provides a mock function with given fields: userID
-func (_m *DBService) GetUser(userID string) (golearn.User, error) {
-	ret := _m.Called(userID)
+// GetUser provides a mock function with given fields: ctx, userID
+func (_m *DBService) GetUser(ctx context.Context, userID string) (golearn.User, error) {
+	ret := _m.Called(ctx, userID)
 
 	var r0 golearn.User
-	if rf, ok := ret.Get(0).(func(string) golearn.User); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) golearn.User); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		r0 = ret.Get(0).(golearn.User)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -136,13 +182,13 @@ func (_m *DBService) GetUser(userID string) (golearn.User, error) {
 	return r0, r1
 }
 
-// InsertActivity provides a mock function with given fields: activity
-func (_m *DBService) InsertActivity(activity golearn.Activity) error {
-	ret := _m.Called(activity)
+// InsertActivity provides a mock function with given fields: ctx, activity
+func (_m *DBService) InsertActivity(ctx context.Context, activity golearn.Activity) error {
+	ret := _m.Called(ctx, activity)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(golearn.Activity) error); ok {
-		r0 = rf(activity)
+	if rf, ok := ret.Get(0).(func(context.Context, golearn.Activity) error); ok {
+		r0 = rf(ctx, activity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -150,13 +196,13 @@ func (_m *DBService) InsertActivity(activity golearn.Activity) error {
 	return r0
 }
 
-// InsertUser provides a mock function with given fields: user
-func (_m *DBService) InsertUser(user golearn.User) error {
-	ret := _m.Called(user)
+// InsertUser provides a mock function with given fields: ctx, user
+func (_m *DBService) InsertUser(ctx context.Context, user golearn.User) error {
+	ret := _m.Called(ctx, user)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(golearn.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, golearn.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -164,13 +210,13 @@ func (_m *DBService) InsertUser(user golearn.User) error {
 	return r0
 }
 
-// InsertWord provides a mock function with given fields: _a0
-func (_m *DBService) InsertWord(_a0 golearn.Row) error {
-	ret := _m.Called(_a0)
+// InsertWord provides a mock function with given fields: ctx, row
+func (_m *DBService) InsertWord(ctx context.Context, row golearn.Row) error {
+	ret := _m.Called(ctx, row)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(golearn.Row) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, golearn.Row) error); ok {
+		r0 = rf(ctx, row)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -178,13 +224,34 @@ func (_m *DBService) InsertWord(_a0 golearn.Row) error {
 	return r0
 }
 
-// RandomAnswers provides a mock function with given fields: q, limit
-func (_m *DBService) RandomAnswers(q golearn.Row, limit int) ([]golearn.Row, error) {
-	ret := _m.Called(q, limit)
+// NextDueQuestion provides a mock function with given fields: ctx, userID, category, now, exclude
+func (_m *DBService) NextDueQuestion(ctx context.Context, userID string, category string, now time.Time, exclude []string) (golearn.Row, error) {
+	ret := _m.Called(ctx, userID, category, now, exclude)
+
+	var r0 golearn.Row
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, []string) golearn.Row); ok {
+		r0 = rf(ctx, userID, category, now, exclude)
+	} else {
+		r0 = ret.Get(0).(golearn.Row)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, []string) error); ok {
+		r1 = rf(ctx, userID, category, now, exclude)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RandomAnswers provides a mock function with given fields: ctx, userID, q, limit
+func (_m *DBService) RandomAnswers(ctx context.Context, userID string, q golearn.Row, limit int) ([]golearn.Row, error) {
+	ret := _m.Called(ctx, userID, q, limit)
 
 	var r0 []golearn.Row
-	if rf, ok := ret.Get(0).(func(golearn.Row, int) []golearn.Row); ok {
-		r0 = rf(q, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, golearn.Row, int) []golearn.Row); ok {
+		r0 = rf(ctx, userID, q, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]golearn.Row)
@@ -192,8 +259,8 @@ func (_m *DBService) RandomAnswers(q golearn.Row, limit int) ([]golearn.Row, err
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(golearn.Row, int) error); ok {
-		r1 = rf(q, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, string, golearn.Row, int) error); ok {
+		r1 = rf(ctx, userID, q, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -201,20 +268,43 @@ func (_m *DBService) RandomAnswers(q golearn.Row, limit int) ([]golearn.Row, err
 	return r0, r1
 }
 
-// RandomQuestion provides a mock function with given fields: category
-func (_m *DBService) RandomQuestion(category string) (golearn.Row, error) {
-	ret := _m.Called(category)
+// RandomQuestion provides a mock function with given fields: ctx, userID, category, exclude
+func (_m *DBService) RandomQuestion(ctx context.Context, userID string, category string, exclude []string) (golearn.Row, error) {
+	ret := _m.Called(ctx, userID, category, exclude)
 
 	var r0 golearn.Row
-	if rf, ok := ret.Get(0).(func(string) golearn.Row); ok {
-		r0 = rf(category)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) golearn.Row); ok {
+		r0 = rf(ctx, userID, category, exclude)
 	} else {
 		r0 = ret.Get(0).(golearn.Row)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(category)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, userID, category, exclude)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecentWords provides a mock function with given fields: ctx, userKey, limit
+func (_m *DBService) RecentWords(ctx context.Context, userKey string, limit int) ([]string, error) {
+	ret := _m.Called(ctx, userKey, limit)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []string); ok {
+		r0 = rf(ctx, userKey, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, userKey, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -222,13 +312,69 @@ func (_m *DBService) RandomQuestion(category string) (golearn.Row, error) {
 	return r0, r1
 }
 
-// ResetState provides a mock function with given fields: _a0
-func (_m *DBService) ResetState(_a0 string) error {
-	ret := _m.Called(_a0)
+// ResetState provides a mock function with given fields: ctx, userKey
+func (_m *DBService) ResetState(ctx context.Context, userKey string) error {
+	ret := _m.Called(ctx, userKey)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetReview provides a mock function with given fields: ctx, review
+func (_m *DBService) SetReview(ctx context.Context, review golearn.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, golearn.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetState provides a mock function with given fields: ctx, state
+func (_m *DBService) SetState(ctx context.Context, state golearn.State) error {
+	ret := _m.Called(ctx, state)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, golearn.State) error); ok {
+		r0 = rf(ctx, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserAnswers provides a mock function with given fields: ctx, userID, count
+func (_m *DBService) SetUserAnswers(ctx context.Context, userID string, count int) error {
+	ret := _m.Called(ctx, userID, count)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, userID, count)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserCategory provides a mock function with given fields: ctx, userID, category
+func (_m *DBService) SetUserCategory(ctx context.Context, userID string, category string) error {
+	ret := _m.Called(ctx, userID, category)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, category)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -236,13 +382,13 @@ func (_m *DBService) ResetState(_a0 string) error {
 	return r0
 }
 
-// SetState provides a mock function with given fields: _a0
-func (_m *DBService) SetState(_a0 golearn.State) error {
-	ret := _m.Called(_a0)
+// SetUserDirection provides a mock function with given fields: ctx, userID, direction
+func (_m *DBService) SetUserDirection(ctx context.Context, userID string, direction string) error {
+	ret := _m.Called(ctx, userID, direction)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(golearn.State) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, direction)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -250,13 +396,13 @@ func (_m *DBService) SetState(_a0 golearn.State) error {
 	return r0
 }
 
-// SetUserCategory provides a mock function with given fields: userID, category
-func (_m *DBService) SetUserCategory(userID string, category string) error {
-	ret := _m.Called(userID, category)
+// SetUserLatinInput provides a mock function with given fields: ctx, userID, enabled
+func (_m *DBService) SetUserLatinInput(ctx context.Context, userID string, enabled bool) error {
+	ret := _m.Called(ctx, userID, enabled)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(userID, category)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, userID, enabled)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -264,13 +410,13 @@ func (_m *DBService) SetUserCategory(userID string, category string) error {
 	return r0
 }
 
-// SetUserMode provides a mock function with given fields: userID, mode
-func (_m *DBService) SetUserMode(userID string, mode string) error {
-	ret := _m.Called(userID, mode)
+// SetUserMode provides a mock function with given fields: ctx, userID, mode
+func (_m *DBService) SetUserMode(ctx context.Context, userID string, mode string) error {
+	ret := _m.Called(ctx, userID, mode)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(userID, mode)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, mode)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -278,13 +424,13 @@ func (_m *DBService) SetUserMode(userID string, mode string) error {
 	return r0
 }
 
-// UpdateUser provides a mock function with given fields: user
-func (_m *DBService) UpdateUser(user golearn.User) error {
-	ret := _m.Called(user)
+// UpdateUser provides a mock function with given fields: ctx, user
+func (_m *DBService) UpdateUser(ctx context.Context, user golearn.User) error {
+	ret := _m.Called(ctx, user)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(golearn.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, golearn.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2,6 +2,7 @@
 
 package mocks
 
+import context "context"
 import golearn "github.com/sergeiten/golearn"
 import http "net/http"
 import mock "github.com/stretchr/testify/mock"
@@ -11,6 +12,20 @@ type HttpService struct {
 	mock.Mock
 }
 
+// AnswerCallback provides a mock function with given fields: ctx, update
+func (_m *HttpService) AnswerCallback(ctx context.Context, update *golearn.Update) error {
+	ret := _m.Called(ctx, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *golearn.Update) error); ok {
+		r0 = rf(ctx, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Parse provides a mock function with given fields: r
 func (_m *HttpService) Parse(r *http.Request) (*golearn.Update, error) {
 	ret := _m.Called(r)
@@ -34,13 +49,13 @@ func (_m *HttpService) Parse(r *http.Request) (*golearn.Update, error) {
 	return r0, r1
 }
 
-// Send provides a mock function with given fields: update, message, keyboard
-func (_m *HttpService) Send(update *golearn.Update, message string, keyboard string) error {
-	ret := _m.Called(update, message, keyboard)
+// Send provides a mock function with given fields: ctx, update, message, keyboard
+func (_m *HttpService) Send(ctx context.Context, update *golearn.Update, message string, keyboard string) error {
+	ret := _m.Called(ctx, update, message, keyboard)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*golearn.Update, string, string) error); ok {
-		r0 = rf(update, message, keyboard)
+	if rf, ok := ret.Get(0).(func(context.Context, *golearn.Update, string, string) error); ok {
+		r0 = rf(ctx, update, message, keyboard)
 	} else {
 		r0 = ret.Error(0)
 	}
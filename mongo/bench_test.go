@@ -0,0 +1,228 @@
+package mongo
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// benchmarkWords is count of words in database of benchmarks.
+const benchmarkWords = 100000
+
+// benchmarkUser asks questions in benchmarks, the user has no own words.
+const benchmarkUser = "177374215"
+
+// benchmarkService returns service of database with benchmarkWords words with random keys.
+// Words are inserted only once, the database is kept for the next runs.
+func benchmarkService(b *testing.B) *Service {
+	cfg := &golearn.Config{}
+
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = "27017"
+	cfg.Database.Name = "benchmark"
+	cfg.Database.ConnectRetries = 1
+
+	service, err := New(cfg)
+	if err != nil {
+		b.Fatalf("failed to connect to benchmark db: %v", err)
+	}
+
+	ctx := context.Background()
+	words := service.db.Collection(wordsCollection)
+
+	count, err := words.CountDocuments(ctx, bson.M{"random": bson.M{"$exists": true}})
+	if err != nil {
+		b.Fatalf("failed to count words: %v", err)
+	}
+
+	if count == benchmarkWords {
+		return service
+	}
+
+	err = words.Drop(ctx)
+	if err != nil {
+		b.Fatalf("failed to drop words: %v", err)
+	}
+
+	var batch []interface{}
+	for i := 0; i < benchmarkWords; i++ {
+		batch = append(batch, word{
+			Row: golearn.Row{
+				Word:      "word " + strconv.Itoa(i),
+				Translate: "translate " + strconv.Itoa(i),
+				Category:  "category " + strconv.Itoa(i%100),
+			},
+			Random: rand.Float64(),
+		})
+
+		if len(batch) == 1000 {
+			_, err = words.InsertMany(ctx, batch)
+			if err != nil {
+				b.Fatalf("failed to insert words: %v", err)
+			}
+			batch = nil
+		}
+	}
+
+	_, err = words.Indexes().CreateMany(ctx, append(indexes[wordsCollection], randomKeyIndexes...))
+	if err != nil {
+		b.Fatalf("failed to create indexes: %v", err)
+	}
+
+	return service
+}
+
+// matchAndSample picks random words the way it was done before random keys:
+// every matching word is read and random ones are picked by server.
+func matchAndSample(ctx context.Context, s *Service, filter bson.M, size int) ([]golearn.Row, error) {
+	var rows []golearn.Row
+
+	cursor, err := s.db.Collection(wordsCollection).Aggregate(ctx, []bson.M{
+		{
+			"$match": filter,
+		},
+		{
+			"$sample": bson.M{
+				"size": size,
+			},
+		},
+	})
+	if err != nil {
+		return rows, err
+	}
+
+	err = cursor.All(ctx, &rows)
+
+	return rows, err
+}
+
+// countAndSkip picks random words the way it was done before $sample:
+// matching words are counted first and then skipped up to random one.
+func countAndSkip(ctx context.Context, s *Service, filter bson.M, size int) ([]golearn.Row, error) {
+	words := s.db.Collection(wordsCollection)
+
+	count, err := words.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var skip int64
+	if count > int64(size) {
+		skip = rand.Int63n(count - int64(size) + 1)
+	}
+
+	var rows []golearn.Row
+	cursor, err := words.Find(ctx, filter, options.Find().SetSkip(skip).SetLimit(int64(size)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(ctx, &rows)
+
+	return rows, err
+}
+
+// benchmarkSampling compares random keys with previous ways of picking, all of them pick size words of the same filter.
+func benchmarkSampling(b *testing.B, filter bson.M, size int) {
+	s := benchmarkService(b)
+	defer s.Close()
+
+	ctx := context.Background()
+
+	b.Run("random key", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := s.sample(ctx, filter, size)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("match and sample", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := matchAndSample(ctx, s, filter, size)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("count and skip", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := countAndSkip(ctx, s, filter, size)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkRandomQuestion picks question of any category skipping recently asked words
+// as RandomQuestion does.
+func BenchmarkRandomQuestion(b *testing.B) {
+	benchmarkSampling(b, bson.M{
+		"owner": visibleTo(benchmarkUser),
+		"word": bson.M{
+			"$nin": []string{"word 1", "word 2", "word 3", "word 4", "word 5"},
+		},
+	}, 1)
+}
+
+// BenchmarkRandomAnswers picks wrong answers of the question category
+// as the first sampling of RandomAnswers with 4 answers does.
+func BenchmarkRandomAnswers(b *testing.B) {
+	benchmarkSampling(b, bson.M{
+		"word": bson.M{
+			"$ne": "word 0",
+		},
+		"owner":    visibleTo(benchmarkUser),
+		"category": "category 0",
+	}, 9)
+}
+
+// BenchmarkUnseenQuestion picks question user has never reviewed as NextDueQuestion does
+// if nothing is due, the user has reviewed every tenth word.
+func BenchmarkUnseenQuestion(b *testing.B) {
+	s := benchmarkService(b)
+	defer s.Close()
+
+	ctx := context.Background()
+	reviews := s.db.Collection(reviewsCollection)
+
+	err := reviews.Drop(ctx)
+	if err != nil {
+		b.Fatalf("failed to drop reviews: %v", err)
+	}
+
+	var batch []interface{}
+	for i := 0; i < benchmarkWords; i += 10 {
+		batch = append(batch, golearn.NewReview(benchmarkUser, golearn.Row{
+			Word:     "word " + strconv.Itoa(i),
+			Category: "category " + strconv.Itoa(i%100),
+		}))
+	}
+
+	_, err = reviews.InsertMany(ctx, batch)
+	if err != nil {
+		b.Fatalf("failed to insert reviews: %v", err)
+	}
+
+	_, err = reviews.Indexes().CreateMany(ctx, indexes[reviewsCollection])
+	if err != nil {
+		b.Fatalf("failed to create indexes: %v", err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := s.sample(ctx, bson.M{"owner": visibleTo(benchmarkUser)}, 1, unseenBy(benchmarkUser)...)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
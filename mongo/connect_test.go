@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientOptions(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.Host = "db"
+	cfg.Database.Port = "27017"
+
+	opts := clientOptions(cfg, time.Second)
+
+	assert.Equal(t, []string{"db:27017"}, opts.Hosts)
+	assert.Nil(t, opts.Auth)
+	assert.Nil(t, opts.TLSConfig)
+	assert.Nil(t, opts.MaxPoolSize)
+	assert.Equal(t, time.Second, *opts.ConnectTimeout)
+
+	cfg.Database.URI = "mongodb://cluster0.example.com:27018/?replicaSet=rs0"
+	cfg.Database.User = "golearn"
+	cfg.Database.Password = "secret"
+	cfg.Database.AuthSource = "admin"
+	cfg.Database.TLS = true
+	cfg.Database.MaxPoolSize = 20
+
+	opts = clientOptions(cfg, time.Second)
+
+	assert.Equal(t, []string{"cluster0.example.com:27018"}, opts.Hosts)
+	assert.Equal(t, "rs0", *opts.ReplicaSet)
+	assert.Equal(t, "golearn", opts.Auth.Username)
+	assert.Equal(t, "secret", opts.Auth.Password)
+	assert.Equal(t, "admin", opts.Auth.AuthSource)
+	assert.NotNil(t, opts.TLSConfig)
+	assert.Equal(t, uint64(20), *opts.MaxPoolSize)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(0))
+	assert.Equal(t, 2*time.Second, backoff(1))
+	assert.Equal(t, 16*time.Second, backoff(4))
+	assert.Equal(t, maxBackoff, backoff(5))
+	assert.Equal(t, maxBackoff, backoff(100))
+}
+
+func TestConnectRetries(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = "1"
+
+	timeout := 50 * time.Millisecond
+
+	var delays []time.Duration
+	client, err := connect(clientOptions(cfg, timeout), timeout, 3, func(d time.Duration) {
+		delays = append(delays, d)
+	})
+
+	assert.NotNil(t, err)
+	assert.Nil(t, client)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, delays)
+}
+
+func TestNewWithInvalidURI(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.URI = "127.0.0.1:27017"
+
+	start := time.Now()
+	service, err := New(cfg)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, service)
+	// options are checked before connection is retried
+	assert.True(t, time.Since(start) < time.Second)
+}
@@ -0,0 +1,334 @@
+package mongo
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	driver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migrationsCollection keeps record of every applied migration.
+const migrationsCollection = "migrations"
+
+// Migration changes indexes or documents of database to the next version.
+// Up has to be safe to run again, so migration interrupted before it's recorded
+// or run by several instances at once doesn't break the database.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *driver.Database) error
+}
+
+// migrationRecord is document of applied migration.
+type migrationRecord struct {
+	Version     int       `bson:"version"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedat"`
+}
+
+// Migrations are applied in order, version of every next migration is greater by one.
+// Applied migrations must never be changed, add new one instead.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create indexes of queried fields",
+		Up:          createIndexes,
+	},
+	{
+		Version:     2,
+		Description: "set empty category of words without category",
+		Up:          backfillWordCategory,
+	},
+	{
+		Version:     3,
+		Description: "remove duplicate users and reviews and make their indexes unique",
+		Up:          uniqueUsersAndReviews,
+	},
+	{
+		Version:     4,
+		Description: "add random keys of words and index them",
+		Up:          randomWordKeys,
+	},
+}
+
+// Version returns version of the last applied migration, zero if there is no one.
+func (s Service) Version(ctx context.Context) (int, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	record := migrationRecord{}
+	latest := options.FindOne().SetSort(bson.M{"version": -1})
+
+	err := s.db.Collection(migrationsCollection).FindOne(ctx, bson.M{}, latest).Decode(&record)
+	if err == driver.ErrNoDocuments {
+		return 0, nil
+	}
+
+	return record.Version, err
+}
+
+// Pending returns migrations which haven't been applied yet.
+func (s Service) Pending(ctx context.Context) ([]Migration, error) {
+	version, err := s.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pending(Migrations, version), nil
+}
+
+// Migrate applies pending migrations and returns applied ones.
+// Migrations aren't limited by timeout of database call, building index
+// of big collection takes a while, ctx of caller is used instead.
+func (s Service) Migrate(ctx context.Context) ([]Migration, error) {
+	migrations, err := s.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		err = m.Up(ctx, s.db)
+		if err != nil {
+			return applied, err
+		}
+
+		_, err = s.db.Collection(migrationsCollection).InsertOne(ctx, migrationRecord{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now(),
+		})
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+// pending returns migrations with version greater than passed one.
+func pending(migrations []Migration, version int) []Migration {
+	var result []Migration
+	for _, m := range migrations {
+		if m.Version > version {
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+// indexes are indexes of collections used by Service queries.
+var indexes = map[string][]driver.IndexModel{
+	usersCollection: {
+		{Keys: bson.D{{Key: "userid", Value: 1}}},
+	},
+	statesCollection: {
+		{Keys: bson.D{{Key: "userkey", Value: 1}, {Key: "timestamp", Value: -1}}},
+	},
+	wordsCollection: {
+		{Keys: bson.D{{Key: "category", Value: 1}}},
+		{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "category", Value: 1}}},
+		{Keys: bson.D{{Key: "word", Value: 1}}},
+	},
+	activitiesCollection: {
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "timestamp", Value: 1}}},
+	},
+	reviewsCollection: {
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "due", Value: 1}}},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "word", Value: 1}, {Key: "category", Value: 1}}},
+	},
+}
+
+// indexNotFound is code of error returned on drop of index which doesn't exist.
+const indexNotFound = 27
+
+// uniqueIndexes replace non-unique indexes created by the first migration,
+// so concurrent inserts and upserts can't create duplicates.
+var uniqueIndexes = []struct {
+	collection string
+	replaces   string
+	model      driver.IndexModel
+}{
+	{
+		collection: usersCollection,
+		replaces:   "userid_1",
+		model: driver.IndexModel{
+			Keys:    bson.D{{Key: "userid", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	{
+		collection: reviewsCollection,
+		replaces:   "userid_1_word_1_category_1",
+		model: driver.IndexModel{
+			Keys:    bson.D{{Key: "userid", Value: 1}, {Key: "word", Value: 1}, {Key: "category", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+}
+
+// createIndexes creates indexes, existing ones are kept as is.
+func createIndexes(ctx context.Context, db *driver.Database) error {
+	for collection, models := range indexes {
+		_, err := db.Collection(collection).Indexes().CreateMany(ctx, models)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uniqueUsersAndReviews removes duplicates of users and reviews and makes their indexes unique.
+// The first saved document of duplicates is kept, it's the one which has been found by queries.
+func uniqueUsersAndReviews(ctx context.Context, db *driver.Database) error {
+	for _, index := range uniqueIndexes {
+		collection := db.Collection(index.collection)
+
+		err := removeDuplicates(ctx, collection, index.model.Keys.(bson.D))
+		if err != nil {
+			return err
+		}
+
+		// index with the same keys can't be created while the old one exists
+		_, err = collection.Indexes().DropOne(ctx, index.replaces)
+		if e, ok := err.(driver.CommandError); err != nil && !(ok && e.Code == indexNotFound) {
+			return err
+		}
+
+		_, err = collection.Indexes().CreateOne(ctx, index.model)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeDuplicates deletes documents of the collection with the same values of keys
+// except the first saved one.
+func removeDuplicates(ctx context.Context, collection *driver.Collection, keys bson.D) error {
+	group := bson.M{}
+	for _, k := range keys {
+		group[k.Key] = "$" + k.Key
+	}
+
+	cursor, err := collection.Aggregate(ctx, []bson.M{
+		{
+			"$sort": bson.M{
+				"_id": 1,
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id": group,
+				"ids": bson.M{
+					"$push": "$_id",
+				},
+			},
+		},
+		{
+			"$match": bson.M{
+				"ids.1": bson.M{
+					"$exists": true,
+				},
+			},
+		},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+
+	var duplicates []struct {
+		IDs []interface{} `bson:"ids"`
+	}
+	err = cursor.All(ctx, &duplicates)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range duplicates {
+		_, err = collection.DeleteMany(ctx, bson.M{
+			"_id": bson.M{
+				"$in": d.IDs[1:],
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillWordCategory sets empty category of words saved before words had categories,
+// otherwise they are grouped to category without name.
+func backfillWordCategory(ctx context.Context, db *driver.Database) error {
+	_, err := db.Collection(wordsCollection).UpdateMany(ctx, bson.M{
+		"category": bson.M{
+			"$exists": false,
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"category": "",
+		},
+	})
+
+	return err
+}
+
+// randomKeyIndexes let random words be found by owner and category without reading other words.
+var randomKeyIndexes = []driver.IndexModel{
+	{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "category", Value: 1}, {Key: "random", Value: 1}}},
+	{Keys: bson.D{{Key: "owner", Value: 1}, {Key: "random", Value: 1}}},
+}
+
+// randomWordKeys sets random key of words saved before words had it and indexes the key.
+func randomWordKeys(ctx context.Context, db *driver.Database) error {
+	words := db.Collection(wordsCollection)
+
+	cursor, err := words.Find(ctx, bson.M{
+		"random": bson.M{
+			"$exists": false,
+		},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var updates []driver.WriteModel
+	for cursor.Next(ctx) {
+		updates = append(updates, driver.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": cursor.Current.Lookup("_id")}).
+			SetUpdate(bson.M{"$set": bson.M{"random": rand.Float64()}}))
+
+		if len(updates) == 1000 {
+			_, err = words.BulkWrite(ctx, updates)
+			if err != nil {
+				return err
+			}
+			updates = nil
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	if len(updates) > 0 {
+		_, err = words.BulkWrite(ctx, updates)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = words.Indexes().CreateMany(ctx, randomKeyIndexes)
+
+	return err
+}
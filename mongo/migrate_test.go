@@ -0,0 +1,138 @@
+package mongo
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	driver "go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMigrationsOrder(t *testing.T) {
+	for i, m := range Migrations {
+		assert.Equal(t, i+1, m.Version, "migration %q is out of order", m.Description)
+		assert.NotEmpty(t, m.Description)
+		assert.NotNil(t, m.Up)
+	}
+}
+
+func TestPending(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}}
+
+	testCases := map[string]struct {
+		Version  int
+		Expected []Migration
+	}{
+		"new database": {
+			Version:  0,
+			Expected: migrations,
+		},
+		"partly migrated": {
+			Version:  2,
+			Expected: migrations[2:],
+		},
+		"up to date": {
+			Version:  3,
+			Expected: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, pending(migrations, tc.Version))
+		})
+	}
+}
+
+func TestService_Migrate(t *testing.T) {
+	if err := prepare(false); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// word saved before words had categories
+	_, err := dbService.db.Collection(wordsCollection).InsertOne(ctx, bson.M{
+		"word":      "가다",
+		"translate": "to go",
+	})
+	assert.Nil(t, err)
+
+	// duplicates saved before indexes became unique
+	for i := 0; i < 2; i++ {
+		_, err = dbService.db.Collection(usersCollection).InsertOne(ctx, golearn.User{UserID: "177374215", Name: "Sergei " + strconv.Itoa(i)})
+		assert.Nil(t, err)
+
+		review := golearn.NewReview("177374215", golearn.Row{Word: "가다", Category: "verbs"})
+		review.Box = i
+		_, err = dbService.db.Collection(reviewsCollection).InsertOne(ctx, review)
+		assert.Nil(t, err)
+	}
+
+	version, err := dbService.Version(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, version)
+
+	applied, err := dbService.Migrate(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, applied, len(Migrations))
+
+	version, err = dbService.Version(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, len(Migrations), version)
+
+	applied, err = dbService.Migrate(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, applied)
+
+	var names []string
+	cursor, err := dbService.db.Collection(statesCollection).Indexes().List(ctx)
+	assert.Nil(t, err)
+	for cursor.Next(ctx) {
+		names = append(names, cursor.Current.Lookup("name").StringValue())
+	}
+	assert.Contains(t, names, "userkey_1_timestamp_-1")
+
+	// the first saved duplicate is kept
+	user, err := dbService.GetUser(ctx, "177374215")
+	assert.Nil(t, err)
+	assert.Equal(t, "Sergei 0", user.Name)
+
+	count, err := dbService.db.Collection(usersCollection).CountDocuments(ctx, bson.M{"userid": "177374215"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	review, err := dbService.GetReview(ctx, "177374215", golearn.Row{Word: "가다", Category: "verbs"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, review.Box)
+
+	count, err = dbService.db.Collection(reviewsCollection).CountDocuments(ctx, bson.M{"userid": "177374215"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// users and reviews can't be duplicated any more
+	_, err = dbService.db.Collection(usersCollection).InsertOne(ctx, golearn.User{UserID: "177374215"})
+	assert.True(t, driver.IsDuplicateKeyError(err))
+
+	assert.Nil(t, dbService.InsertUser(ctx, golearn.User{UserID: "177374215"}))
+
+	categories, err := dbService.GetCategories(ctx, "")
+	assert.Nil(t, err)
+	assert.Empty(t, categories)
+
+	count, err = dbService.db.Collection(wordsCollection).CountDocuments(ctx, bson.M{"category": ""})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// word saved before words had random keys can be asked
+	count, err = dbService.db.Collection(wordsCollection).CountDocuments(ctx, bson.M{"random": bson.M{"$exists": false}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	row, err := dbService.RandomQuestion(ctx, "177374215", "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "가다", row.Word)
+}
@@ -1,14 +1,19 @@
 package mongo
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"log"
 	"math/rand"
 	"time"
 
 	"github.com/sergeiten/golearn"
-	"gopkg.in/mgo.v2"
-	"gopkg.in/mgo.v2/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	driver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 const (
@@ -16,204 +21,626 @@ const (
 	statesCollection     = "states"
 	wordsCollection      = "words"
 	activitiesCollection = "stats"
+	reviewsCollection    = "reviews"
 )
 
+// DefaultConnectRetries is used if config doesn't set count of attempts to connect.
+const DefaultConnectRetries = 5
+
+// maxBackoff limits delay between attempts to connect.
+const maxBackoff = 30 * time.Second
+
+// word is document of words collection. Random is key of random picks,
+// it's indexed with owner and category of the word.
+type word struct {
+	golearn.Row `bson:",inline"`
+	Random      float64
+}
+
 // Service of mongodb
 type Service struct {
-	session *mgo.Session
-	db      string
+	client  *driver.Client
+	db      *driver.Database
+	timeout time.Duration
 }
 
-// New returns new instance of mockService
+// New returns new instance of Service.
+// Connection is retried with growing delay, so app can be started before database is ready.
+// Pending migrations are applied if config asks for it.
 func New(cfg *golearn.Config) (*Service, error) {
-	session, err := newSession(cfg)
+	timeout := cfg.Database.CallTimeout()
+
+	retries := DefaultConnectRetries
+	if cfg.Database.ConnectRetries > 0 {
+		retries = cfg.Database.ConnectRetries
+	}
+
+	client, err := connect(clientOptions(cfg, timeout), timeout, retries, time.Sleep)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Service{
-		session: session,
-		db:      cfg.Database.Name,
-	}, nil
+	s := &Service{
+		client:  client,
+		db:      client.Database(cfg.Database.Name),
+		timeout: timeout,
+	}
+
+	if cfg.Database.Migrate {
+		applied, err := s.Migrate(context.Background())
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+
+		for _, m := range applied {
+			log.Printf("applied mongodb migration %d: %s", m.Version, m.Description)
+		}
+	}
+
+	return s, nil
 }
 
-func newSession(cfg *golearn.Config) (*mgo.Session, error) {
-	connString := fmt.Sprintf("%s:%s", cfg.Database.Host, cfg.Database.Port)
+// clientOptions returns options of client from config. URI is used if it's set,
+// otherwise it's built from host and port. Credentials from config override ones of URI.
+func clientOptions(cfg *golearn.Config, timeout time.Duration) *options.ClientOptions {
+	uri := cfg.Database.URI
+	if uri == "" {
+		uri = fmt.Sprintf("mongodb://%s:%s", cfg.Database.Host, cfg.Database.Port)
+	}
 
-	if cfg.Database.Delay > 0 {
-		time.Sleep(time.Duration(cfg.Database.Delay) * time.Second)
+	opts := options.Client().
+		ApplyURI(uri).
+		SetConnectTimeout(timeout).
+		SetServerSelectionTimeout(timeout)
+
+	if cfg.Database.User != "" {
+		opts.SetAuth(options.Credential{
+			AuthSource: cfg.Database.AuthSource,
+			Username:   cfg.Database.User,
+			Password:   cfg.Database.Password,
+		})
 	}
 
-	timeout := time.Duration(5 * time.Second)
-	session, err := mgo.DialWithTimeout(connString, timeout)
+	// empty config verifies server by system root certificates
+	if cfg.Database.TLS {
+		opts.SetTLSConfig(&tls.Config{})
+	}
 
+	if cfg.Database.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(uint64(cfg.Database.MaxPoolSize))
+	}
+
+	return opts
+}
+
+// connect returns client connected by passed options and checks that server is available.
+// Invalid options fail at once, failed pings are retried after backoff delay.
+func connect(opts *options.ClientOptions, timeout time.Duration, retries int, sleep func(time.Duration)) (*driver.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	client, err := driver.Connect(ctx, opts)
+	cancel()
 	if err != nil {
-		return session, err
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err = client.Ping(ctx, readpref.Primary())
+		cancel()
+
+		if err == nil {
+			return client, nil
+		}
+
+		if attempt >= retries {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			golearn.LogPrint(client.Disconnect(ctx), "failed to disconnect from mongodb")
+			cancel()
+
+			return nil, err
+		}
+
+		golearn.LogPrint(err, "failed to connect to mongodb, retrying")
+		sleep(backoff(attempt))
 	}
-	session.SetMode(mgo.Monotonic, true)
+}
+
+// backoff returns delay before next attempt to connect,
+// it's doubled after every failed attempt.
+func backoff(attempt int) time.Duration {
+	delay := time.Second << uint(attempt)
+	if delay <= 0 || delay > maxBackoff {
+		return maxBackoff
+	}
+
+	return delay
+}
 
-	return session, nil
+// withTimeout returns ctx limited by timeout of database call.
+func (s Service) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.timeout)
 }
 
-// Close terminates the mockService session
+// Close disconnects client from database
 func (s Service) Close() {
-	s.session.Close()
+	ctx, cancel := s.withTimeout(context.Background())
+	defer cancel()
+
+	err := s.client.Disconnect(ctx)
+	golearn.LogPrint(err, "failed to disconnect from mongodb")
+}
+
+// RandomQuestion returns random row of global words and words of passed user.
+// Words in exclude are skipped unless there is no other word.
+func (s Service) RandomQuestion(ctx context.Context, userID string, category string, exclude []string) (golearn.Row, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return golearn.ExcludeRecent(exclude, func(exclude []string) (golearn.Row, error) {
+		r := golearn.Row{}
+
+		condition := bson.M{
+			"owner": visibleTo(userID),
+		}
+
+		if category != "" {
+			condition["category"] = category
+		}
+
+		if len(exclude) > 0 {
+			condition["word"] = bson.M{
+				"$nin": exclude,
+			}
+		}
+
+		rows, err := s.sample(ctx, condition, 1)
+		if err != nil {
+			return r, err
+		}
+
+		if len(rows) == 0 {
+			return r, golearn.ErrNoWords
+		}
+
+		return rows[0], nil
+	})
 }
 
-// RandomQuestion returns random row
-func (s Service) RandomQuestion(category string) (golearn.Row, error) {
+// NextDueQuestion returns the word user has to review next.
+// Word with the most overdue review goes first. If there is nothing to review
+// random word which user has never seen is returned. If user has seen all words
+// the word with the closest review date is returned.
+// Words in exclude are skipped unless there is no other word.
+func (s Service) NextDueQuestion(ctx context.Context, userID string, category string, now time.Time, exclude []string) (golearn.Row, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return golearn.ExcludeRecent(exclude, func(exclude []string) (golearn.Row, error) {
+		return s.nextDueQuestion(ctx, userID, category, now, exclude)
+	})
+}
+
+func (s Service) nextDueQuestion(ctx context.Context, userID string, category string, now time.Time, exclude []string) (golearn.Row, error) {
 	r := golearn.Row{}
 
-	condition := bson.M{}
+	condition := bson.M{
+		"userid": userID,
+	}
+
+	if category != "" {
+		condition["category"] = category
+	}
+
+	if len(exclude) > 0 {
+		condition["word"] = bson.M{
+			"$nin": exclude,
+		}
+	}
+
+	due := bson.M{
+		"$and": []bson.M{
+			condition,
+			{
+				"due": bson.M{
+					"$lte": now,
+				},
+			},
+		},
+	}
+
+	row, found, err := s.firstReviewRow(ctx, due)
+	if err != nil || found {
+		return row, err
+	}
+
+	unseen := bson.M{
+		"owner": visibleTo(userID),
+	}
 
 	if category != "" {
-		condition = bson.M{
-			"category": category,
+		unseen["category"] = category
+	}
+
+	if len(exclude) > 0 {
+		unseen["word"] = bson.M{
+			"$nin": exclude,
 		}
 	}
 
-	count, err := s.session.DB(s.db).C(wordsCollection).Find(condition).Count()
+	rows, err := s.sample(ctx, unseen, 1, unseenBy(userID)...)
 	if err != nil {
 		return r, err
 	}
 
-	query := s.session.DB(s.db).C(wordsCollection).Find(condition).Limit(1).Skip(rand.Intn(count))
+	if len(rows) > 0 {
+		return rows[0], nil
+	}
+
+	row, found, err = s.firstReviewRow(ctx, condition)
+	if err == nil && !found {
+		return r, golearn.ErrNoWords
+	}
 
-	err = query.One(&r)
+	return row, err
+}
+
+// firstReviewRow returns word of the review with the earliest due date among matching filter.
+// Reviews of deleted words are skipped, false is returned if there is no review of existing word.
+func (s Service) firstReviewRow(ctx context.Context, filter interface{}) (golearn.Row, bool, error) {
+	byDue := options.Find().SetSort(bson.M{"due": 1})
+
+	cursor, err := s.db.Collection(reviewsCollection).Find(ctx, filter, byDue)
+	if err != nil {
+		return golearn.Row{}, false, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		review := golearn.Review{}
+		err = cursor.Decode(&review)
+		if err != nil {
+			return golearn.Row{}, false, err
+		}
+
+		row, err := s.reviewRow(ctx, review)
+		if err == driver.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return golearn.Row{}, false, err
+		}
+
+		return row, true, nil
+	}
+
+	return golearn.Row{}, false, cursor.Err()
+}
+
+func (s Service) reviewRow(ctx context.Context, review golearn.Review) (golearn.Row, error) {
+	r := golearn.Row{}
+	err := s.db.Collection(wordsCollection).FindOne(ctx, bson.M{
+		"word":     review.Word,
+		"category": review.Category,
+		"owner":    visibleTo(review.UserID),
+	}).Decode(&r)
 
 	return r, err
 }
 
 // RandomAnswers returns random answers with passed count of total answers.
 // q is right answer which will be appended to result slice and will be shuffled later.
-// count is total amount of answers which is included the right one.
-//
-// In order to get random rows function generates random number which is used as offset.
-// In order to guarantee that function returns exactly passed "count" of words
-// function calculated maximum allowed random number. If generated random number of greater
-// that allowed function uses maximum number.
-//
-// Example:
-// In case if passed count is equal 4. Function has to find 3 random rows and 1 (the passed one)
-// will be appended to result slice.
-// Generated random number is equal 7, that means function returns just 1 rows (8).
-// Function moves cursor to position 5 to return enough rows (6,7,8).
-/*
-                                  Moved         Generated
-                                    +---------------+
-                                    |               |
-                                    v               v
-+-------+-------+-------+-------+---+---+-------+---+---+-------+
-|       |       |       |       |       |       |       |       |
-|   1   |   2   |   3   |   4   |   5   |   6   |   7   |   8   |
-|       |       |       |       |       |       |       |       |
-+-------+-------+-------+-------+-------+-------+-------+-------+
-*/
-func (s Service) RandomAnswers(q golearn.Row, count int) ([]golearn.Row, error) {
-	var r []golearn.Row
-
-	f := bson.M{
-		"word": bson.M{
-			"$ne": q.Word,
-		},
+// count is total amount of answers which is included the right one, fewer answers
+// are returned if there aren't enough words. Wrong answers are picked by golearn.PickDistractors.
+func (s Service) RandomAnswers(ctx context.Context, userID string, q golearn.Row, count int) ([]golearn.Row, error) {
+	if count <= 1 {
+		return []golearn.Row{q}, nil
 	}
 
-	total, err := s.session.DB(s.db).C(wordsCollection).Find(f).Count()
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	r, err := golearn.PickDistractors(q, count-1, func(sameCategory bool, limit int) ([]golearn.Row, error) {
+		filter := bson.M{
+			"word": bson.M{
+				"$ne": q.Word,
+			},
+			"owner":    visibleTo(userID),
+			"category": q.Category,
+		}
+
+		if !sameCategory {
+			filter["category"] = bson.M{
+				"$ne": q.Category,
+			}
+		}
+
+		return s.sample(ctx, filter, limit)
+	})
 	if err != nil {
 		return r, err
 	}
 
-	maxRand := (total - count) + 1
+	return append(r, q), nil
+}
+
+// sample returns up to size random words matching passed filter.
+// Words are read by index of owner, category and random key starting from random point
+// of the key, words with smaller keys follow if there aren't enough of them. So only picked
+// words are read instead of every matching one, however words after bigger gaps between keys
+// are picked a bit more often. Passed stages filter words after they are read by key.
+func (s Service) sample(ctx context.Context, filter bson.M, size int, stages ...bson.M) ([]golearn.Row, error) {
+	point := rand.Float64()
+
+	rows, err := s.keyRange(ctx, filter, bson.M{"$gte": point}, size, stages)
+	if err != nil || len(rows) >= size {
+		return rows, err
+	}
+
+	rest, err := s.keyRange(ctx, filter, bson.M{"$lt": point}, size-len(rows), stages)
 
-	rand.Seed(time.Now().UnixNano())
-	random := rand.Intn(total)
+	return append(rows, rest...), err
+}
 
-	if random > maxRand {
-		random = maxRand
+// keyRange returns up to size words matching filter with random key in passed range ordered by the key.
+func (s Service) keyRange(ctx context.Context, filter bson.M, keys bson.M, size int, stages []bson.M) ([]golearn.Row, error) {
+	match := bson.M{
+		"random": keys,
+	}
+	for field, condition := range filter {
+		match[field] = condition
 	}
 
-	err = s.session.DB(s.db).C(wordsCollection).Find(f).Limit(count - 1).Skip(random).All(&r)
+	pipe := []bson.M{
+		{
+			"$match": match,
+		},
+		{
+			"$sort": bson.M{
+				"random": 1,
+			},
+		},
+	}
+	pipe = append(pipe, stages...)
+	pipe = append(pipe, bson.M{
+		"$limit": size,
+	})
+
+	var rows []golearn.Row
+
+	cursor, err := s.db.Collection(wordsCollection).Aggregate(ctx, pipe)
 	if err != nil {
-		return r, err
+		return rows, err
 	}
 
-	r = append(r, q)
+	err = cursor.All(ctx, &rows)
 
-	return r, nil
+	return rows, err
+}
+
+// unseenBy returns stages which skip words reviewed by passed user.
+// Reviews of every read word are looked up by index of user and word,
+// so reviewed words aren't loaded and sent back with every question.
+func unseenBy(userID string) []bson.M {
+	return []bson.M{
+		{
+			"$lookup": bson.M{
+				"from": reviewsCollection,
+				"let": bson.M{
+					"word": "$word",
+				},
+				"pipeline": []bson.M{
+					{
+						"$match": bson.M{
+							"userid": userID,
+							"$expr": bson.M{
+								"$eq": []string{"$word", "$$word"},
+							},
+						},
+					},
+					{
+						"$limit": 1,
+					},
+				},
+				"as": "reviews",
+			},
+		},
+		{
+			"$match": bson.M{
+				"reviews": bson.M{
+					"$size": 0,
+				},
+			},
+		},
+	}
 }
 
 // SetState save latest given set of question and answers
-func (s Service) SetState(state golearn.State) error {
-	return s.session.DB(s.db).C(statesCollection).Insert(state)
+func (s Service) SetState(ctx context.Context, state golearn.State) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Collection(statesCollection).InsertOne(ctx, state)
+
+	return err
 }
 
 // GetState returns lastest saved user state
-func (s Service) GetState(userKey string) (golearn.State, error) {
+func (s Service) GetState(ctx context.Context, userKey string) (golearn.State, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	state := golearn.State{}
-	err := s.session.DB(s.db).C(statesCollection).Find(bson.M{"userkey": userKey}).Sort("-timestamp").One(&state)
+	latest := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	err := s.db.Collection(statesCollection).FindOne(ctx, bson.M{"userkey": userKey}, latest).Decode(&state)
 
 	return state, err
 }
 
+// RecentWords returns words asked in last limit states of user, the latest first.
+func (s Service) RecentWords(ctx context.Context, userKey string, limit int) ([]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	latest := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := s.db.Collection(statesCollection).Find(ctx, bson.M{"userkey": userKey}, latest)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []golearn.State
+	err = cursor.All(ctx, &states)
+	if err != nil {
+		return nil, err
+	}
+
+	return golearn.StateWords(states), nil
+}
+
 // ResetState resets user state
-func (s Service) ResetState(userKey string) error {
-	return nil
+func (s Service) ResetState(ctx context.Context, userKey string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Collection(statesCollection).DeleteMany(ctx, bson.M{"userkey": userKey})
+
+	return err
 }
 
 // InsertWord inserts new row to words collection
-func (s Service) InsertWord(w golearn.Row) error {
-	return s.session.DB(s.db).C(wordsCollection).Insert(w)
+func (s Service) InsertWord(ctx context.Context, w golearn.Row) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Collection(wordsCollection).InsertOne(ctx, word{Row: w, Random: rand.Float64()})
+
+	return err
 }
 
 // InsertUser inserts new user to users collection
-func (s Service) InsertUser(user golearn.User) error {
-	return s.session.DB(s.db).C(usersCollection).Insert(user)
+func (s Service) InsertUser(ctx context.Context, user golearn.User) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Collection(usersCollection).InsertOne(ctx, user)
+
+	// user has been inserted by concurrent update of the same user
+	if driver.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
 }
 
 // UpdateUser updates user
-func (s Service) UpdateUser(user golearn.User) error {
-	return s.session.DB(s.db).C(usersCollection).Update(bson.M{"userid": user.UserID}, user)
+func (s Service) UpdateUser(ctx context.Context, user golearn.User) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	result, err := s.db.Collection(usersCollection).ReplaceOne(ctx, bson.M{"userid": user.UserID}, user)
+	if err != nil {
+		return err
+	}
+
+	return matched(result)
 }
 
 // ExistUser returns bool if user already exists in db
-func (s Service) ExistUser(user golearn.User) (bool, error) {
+func (s Service) ExistUser(ctx context.Context, user golearn.User) (bool, error) {
 	if user.UserID == "" {
 		return false, errors.New("passed user has empty id")
 	}
-	count, err := s.session.DB(s.db).C(usersCollection).Find(bson.M{"userid": user.UserID}).Count()
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	count, err := s.db.Collection(usersCollection).CountDocuments(ctx, bson.M{"userid": user.UserID})
 	return count > 0, err
 }
 
 // GetUser returns user from db
-func (s Service) GetUser(userID string) (golearn.User, error) {
+func (s Service) GetUser(ctx context.Context, userID string) (golearn.User, error) {
 	u := golearn.User{}
 	if userID == "" {
 		return u, errors.New("passed user id is empty")
 	}
-	err := s.session.DB(s.db).C(usersCollection).Find(bson.M{"userid": userID}).One(&u)
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	err := s.db.Collection(usersCollection).FindOne(ctx, bson.M{"userid": userID}).Decode(&u)
 
 	return u, err
 }
 
 // SetUserMode sets new mode for passed user id
-func (s Service) SetUserMode(userID string, mode string) error {
-	return s.session.DB(s.db).C(usersCollection).Update(bson.M{"userid": userID}, bson.M{
-		"$set": bson.M{
-			"mode": mode,
-		},
+func (s Service) SetUserMode(ctx context.Context, userID string, mode string) error {
+	return s.setUser(ctx, userID, bson.M{
+		"mode": mode,
 	})
 }
 
+// SetUserDirection sets new direction of questions for passed user id
+func (s Service) SetUserDirection(ctx context.Context, userID string, direction string) error {
+	return s.setUser(ctx, userID, bson.M{
+		"direction": direction,
+	})
+}
+
+// SetUserLatinInput enables or disables conversion of answers typed on Latin keyboard for passed user id
+func (s Service) SetUserLatinInput(ctx context.Context, userID string, enabled bool) error {
+	return s.setUser(ctx, userID, bson.M{
+		"latininput": enabled,
+	})
+}
+
+// SetUserAnswers sets count of answers in picking mode for passed user id
+func (s Service) SetUserAnswers(ctx context.Context, userID string, count int) error {
+	return s.setUser(ctx, userID, bson.M{
+		"answers": count,
+	})
+}
+
+// SetUserCategory sets category of questions for passed user id
+func (s Service) SetUserCategory(ctx context.Context, userID string, category string) error {
+	return s.setUser(ctx, userID, bson.M{
+		"category": category,
+	})
+}
+
+// setUser sets passed fields of user.
+func (s Service) setUser(ctx context.Context, userID string, fields bson.M) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	result, err := s.db.Collection(usersCollection).UpdateOne(ctx, bson.M{"userid": userID}, bson.M{
+		"$set": fields,
+	})
+	if err != nil {
+		return err
+	}
+
+	return matched(result)
+}
+
 // GetCategories returns list of unique categories based on words table.
-func (s Service) GetCategories(userID string) ([]golearn.Category, error) {
+// Global categories are returned along with own collections of passed user.
+func (s Service) GetCategories(ctx context.Context, userID string) ([]golearn.Category, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	var categories []golearn.Category
 
-	err := s.session.DB(s.db).C(wordsCollection).Pipe([]bson.M{
+	cursor, err := s.db.Collection(wordsCollection).Aggregate(ctx, []bson.M{
 		{
 			"$match": bson.M{
 				"category": bson.M{
 					"$ne": "",
 				},
+				"owner": visibleTo(userID),
 			},
 		},
 		{
@@ -235,25 +662,36 @@ func (s Service) GetCategories(userID string) ([]golearn.Category, error) {
 				"category": 1,
 			},
 		},
-	}).All(&categories)
+	})
+	if err != nil {
+		return categories, err
+	}
+
+	err = cursor.All(ctx, &categories)
 
 	return categories, err
 }
 
-func (s Service) SetUserCategory(userID string, category string) error {
-	return s.session.DB(s.db).C(usersCollection).Update(bson.M{"userid": userID}, bson.M{
-		"$set": bson.M{
-			"category": category,
-		},
-	})
-}
+// InsertActivity saves user answer.
+func (s Service) InsertActivity(ctx context.Context, activity golearn.Activity) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Collection(activitiesCollection).InsertOne(ctx, activity)
 
-func (s Service) InsertActivity(activity golearn.Activity) error {
-	return s.session.DB(s.db).C(activitiesCollection).Insert(activity)
+	return err
 }
 
-func (s Service) GetStatistics(userID string, year int, month int, week int, day int) (golearn.Statistics, error) {
+// GetStatistics returns count of right and wrong answers of user for passed day, ISO week and month.
+// Week is counted in ISO year of the day.
+func (s Service) GetStatistics(ctx context.Context, userID string, year int, month int, week int, day int) (golearn.Statistics, error) {
 	pipe := []bson.M{
+		{
+			// activities of user are picked by index before they are grouped
+			"$match": bson.M{
+				"userid": userID,
+			},
+		},
 		{
 			"$facet": bson.M{
 				"today": []bson.M{
@@ -262,6 +700,9 @@ func (s Service) GetStatistics(userID string, year int, month int, week int, day
 							"year": bson.M{
 								"$year": "$timestamp",
 							},
+							"month": bson.M{
+								"$month": "$timestamp",
+							},
 							"day": bson.M{
 								"$dayOfMonth": "$timestamp",
 							},
@@ -272,6 +713,7 @@ func (s Service) GetStatistics(userID string, year int, month int, week int, day
 					{
 						"$match": bson.M{
 							"year":   year,
+							"month":  month,
 							"day":    day,
 							"userid": userID,
 						},
@@ -311,7 +753,7 @@ func (s Service) GetStatistics(userID string, year int, month int, week int, day
 					{
 						"$project": bson.M{
 							"year": bson.M{
-								"$year": "$timestamp",
+								"$isoWeekYear": "$timestamp",
 							},
 							"week": bson.M{
 								"$isoWeek": "$timestamp",
@@ -322,7 +764,7 @@ func (s Service) GetStatistics(userID string, year int, month int, week int, day
 					},
 					{
 						"$match": bson.M{
-							"year":   year,
+							"year":   golearn.WeekYear(year, month, day),
 							"week":   week,
 							"userid": userID,
 						},
@@ -414,18 +856,29 @@ func (s Service) GetStatistics(userID string, year int, month int, week int, day
 	}
 
 	var stat struct {
-		Today []golearn.StatRow `json:"today"`
-		Week  []golearn.StatRow `json:"week"`
-		Month []golearn.StatRow `json:"month"`
+		Today []golearn.StatRow `bson:"today"`
+		Week  []golearn.StatRow `bson:"week"`
+		Month []golearn.StatRow `bson:"month"`
 	}
 
 	var statistics golearn.Statistics
 
-	err := s.session.DB(s.db).C(activitiesCollection).Pipe(pipe).One(&stat)
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
+	cursor, err := s.db.Collection(activitiesCollection).Aggregate(ctx, pipe)
 	if err != nil {
 		return statistics, err
 	}
+	defer cursor.Close(ctx)
+
+	// facet returns single document
+	if cursor.Next(ctx) {
+		err = cursor.Decode(&stat)
+		if err != nil {
+			return statistics, err
+		}
+	}
 
 	if len(stat.Today) > 0 {
 		statistics.Today = stat.Today[0]
@@ -437,13 +890,118 @@ func (s Service) GetStatistics(userID string, year int, month int, week int, day
 		statistics.Month = stat.Month[0]
 	}
 
-	return statistics, err
+	return statistics, cursor.Err()
+}
+
+// GetReview returns user review of passed row.
+// New review is returned if user has never reviewed the row.
+func (s Service) GetReview(ctx context.Context, userID string, row golearn.Row) (golearn.Review, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	review := golearn.Review{}
+	err := s.db.Collection(reviewsCollection).FindOne(ctx, bson.M{
+		"userid":   userID,
+		"word":     row.Word,
+		"category": row.Category,
+	}).Decode(&review)
+
+	if err == driver.ErrNoDocuments {
+		return golearn.NewReview(userID, row), nil
+	}
+
+	return review, err
+}
+
+// SetReview inserts or updates user review of the word.
+func (s Service) SetReview(ctx context.Context, review golearn.Review) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.Collection(reviewsCollection).ReplaceOne(ctx, bson.M{
+		"userid":   review.UserID,
+		"word":     review.Word,
+		"category": review.Category,
+	}, review, options.Replace().SetUpsert(true))
+
+	return err
+}
+
+// GetBoxes returns Leitner boxes of user with count of words in each box.
+// Empty boxes are not returned.
+func (s Service) GetBoxes(ctx context.Context, userID string) ([]golearn.Box, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var boxes []golearn.Box
+
+	cursor, err := s.db.Collection(reviewsCollection).Aggregate(ctx, []bson.M{
+		{
+			"$match": bson.M{
+				"userid": userID,
+				"box": bson.M{
+					"$gt": 0,
+				},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id": "$box",
+				"number": bson.M{
+					"$first": "$box",
+				},
+				"words": bson.M{
+					"$sum": 1,
+				},
+			},
+		},
+		{
+			"$sort": bson.M{
+				"number": 1,
+			},
+		},
+	})
+	if err != nil {
+		return boxes, err
+	}
+
+	err = cursor.All(ctx, &boxes)
+
+	return boxes, err
 }
 
-func (s Service) DeleteWordsByCategory(userID string, category string) error {
-	_, err := s.session.DB(s.db).C(wordsCollection).RemoveAll(bson.M{
+// DeleteWordsByCategory removes words of category owned by passed user.
+// Empty user id removes global words only.
+func (s Service) DeleteWordsByCategory(ctx context.Context, userID string, category string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	owner := interface{}(userID)
+	if userID == "" {
+		owner = visibleTo("")
+	}
+
+	_, err := s.db.Collection(wordsCollection).DeleteMany(ctx, bson.M{
 		"category": category,
+		"owner":    owner,
 	})
 
 	return err
 }
+
+// visibleTo returns condition on word owner which matches global words
+// and words of passed user. Global words are saved without owner.
+func visibleTo(userID string) bson.M {
+	return bson.M{
+		"$in": []interface{}{nil, "", userID},
+	}
+}
+
+// matched returns driver.ErrNoDocuments if update hasn't matched any document.
+func matched(result *driver.UpdateResult) error {
+	if result.MatchedCount == 0 {
+		return driver.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -1,11 +1,13 @@
 package mongo
 
 import (
+	"context"
 	"log"
 	"testing"
 	"time"
 
 	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/dbtest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,6 +23,7 @@ func prepare(seeding bool) error {
 	cfg.Database.Host = "127.0.0.1"
 	cfg.Database.Port = "27017"
 	cfg.Database.Name = "test"
+	cfg.Database.ConnectRetries = 1
 	cfg.DefaultLanguage = "ru"
 
 	var err error
@@ -178,14 +181,14 @@ func prepare(seeding bool) error {
 }
 
 func seed() error {
-	err := dbService.InsertUser(testUser)
+	err := dbService.InsertUser(context.Background(), testUser)
 
 	if err != nil {
 		log.Fatalf("failed to insert test user")
 	}
 
 	for _, word := range testWords {
-		err := dbService.InsertWord(word)
+		err := dbService.InsertWord(context.Background(), word)
 
 		if err != nil {
 			log.Fatalf("failed to insert test user")
@@ -193,7 +196,7 @@ func seed() error {
 	}
 
 	for _, activity := range testActivities {
-		err := dbService.InsertActivity(activity)
+		err := dbService.InsertActivity(context.Background(), activity)
 		if err != nil {
 			log.Fatalf("failed to insert activity")
 		}
@@ -203,7 +206,7 @@ func seed() error {
 }
 
 func clean() error {
-	return dbService.session.DB(dbService.db).DropDatabase()
+	return dbService.db.Drop(context.Background())
 }
 
 func TestService_RandomQuestion(t *testing.T) {
@@ -211,7 +214,7 @@ func TestService_RandomQuestion(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	question, err := dbService.RandomQuestion(testUser.Category)
+	question, err := dbService.RandomQuestion(context.Background(), testUser.UserID, testUser.Category, nil)
 
 	assert.Nil(t, err, "failed to get random question")
 	assert.NotEmpty(t, question, "random question is empty")
@@ -223,7 +226,7 @@ func TestService_RandomAnswers(t *testing.T) {
 	}
 
 	count := 4
-	answers, err := dbService.RandomAnswers(testWords[0], count)
+	answers, err := dbService.RandomAnswers(context.Background(), testUser.UserID, testWords[0], count)
 
 	assert.Nil(t, err, "failed to get random answers")
 	assert.NotEmpty(t, answers)
@@ -236,7 +239,7 @@ func TestService_SetState(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	err := dbService.SetState(testState)
+	err := dbService.SetState(context.Background(), testState)
 
 	assert.Nil(t, err, "failed to set user state")
 }
@@ -246,11 +249,11 @@ func TestService_GetState(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	err := dbService.SetState(testState)
+	err := dbService.SetState(context.Background(), testState)
 
 	assert.Nil(t, err)
 
-	state, err := dbService.GetState(testUser.UserID)
+	state, err := dbService.GetState(context.Background(), testUser.UserID)
 
 	assert.Nil(t, err)
 	assert.Equal(t, testState, state)
@@ -261,7 +264,7 @@ func TestService_InsertWord(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	err := dbService.InsertWord(golearn.Row{
+	err := dbService.InsertWord(context.Background(), golearn.Row{
 		Word:      "origin",
 		Translate: "translate",
 	})
@@ -269,16 +272,33 @@ func TestService_InsertWord(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestService_InsertWordWithVariants(t *testing.T) {
+	if err := prepare(false); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	row := golearn.NewRow("가다", "идти; ходить", "verbs")
+
+	err := dbService.InsertWord(context.Background(), row)
+
+	assert.Nil(t, err)
+
+	question, err := dbService.RandomQuestion(context.Background(), testUser.UserID, "verbs", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, row, question)
+}
+
 func TestService_InsertUser(t *testing.T) {
 	if err := prepare(false); err != nil {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	err := dbService.InsertUser(testUser)
+	err := dbService.InsertUser(context.Background(), testUser)
 
 	assert.Nil(t, err)
 
-	user, err := dbService.GetUser(testUser.UserID)
+	user, err := dbService.GetUser(context.Background(), testUser.UserID)
 
 	assert.Nil(t, err)
 	assert.Equal(t, testUser, user)
@@ -296,11 +316,11 @@ func TestService_UpdateUser(t *testing.T) {
 	updatedUser.Category = "new category"
 	updatedUser.Username = "new username"
 
-	err := dbService.UpdateUser(updatedUser)
+	err := dbService.UpdateUser(context.Background(), updatedUser)
 
 	assert.Nil(t, err)
 
-	user, err := dbService.GetUser(updatedUser.UserID)
+	user, err := dbService.GetUser(context.Background(), updatedUser.UserID)
 
 	assert.Nil(t, err)
 	assert.Equal(t, updatedUser, user)
@@ -311,7 +331,7 @@ func TestService_ExistUser(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	exist, err := dbService.ExistUser(testUser)
+	exist, err := dbService.ExistUser(context.Background(), testUser)
 
 	assert.Nil(t, err)
 	assert.Equal(t, true, exist)
@@ -322,7 +342,7 @@ func TestService_GetUser(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	user, err := dbService.GetUser(testUser.UserID)
+	user, err := dbService.GetUser(context.Background(), testUser.UserID)
 
 	assert.Nil(t, err, "failed to get user")
 	assert.Equal(t, testUser, user)
@@ -333,11 +353,11 @@ func TestService_SetUserMode(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	err := dbService.SetUserMode(testUser.UserID, golearn.ModeTyping)
+	err := dbService.SetUserMode(context.Background(), testUser.UserID, golearn.ModeTyping)
 
 	assert.Nil(t, err)
 
-	user, err := dbService.GetUser(testUser.UserID)
+	user, err := dbService.GetUser(context.Background(), testUser.UserID)
 
 	assert.Nil(t, err)
 	assert.Equal(t, golearn.ModeTyping, user.Mode)
@@ -350,11 +370,11 @@ func TestService_SetUserCategory(t *testing.T) {
 
 	testCategory := "test category"
 
-	err := dbService.SetUserCategory(testUser.UserID, testCategory)
+	err := dbService.SetUserCategory(context.Background(), testUser.UserID, testCategory)
 
 	assert.Nil(t, err)
 
-	user, err := dbService.GetUser(testUser.UserID)
+	user, err := dbService.GetUser(context.Background(), testUser.UserID)
 
 	assert.Nil(t, err)
 	assert.Equal(t, testCategory, user.Category)
@@ -376,7 +396,7 @@ func TestService_GetCategories(t *testing.T) {
 		},
 	}
 
-	categories, err := dbService.GetCategories(testUser.UserID)
+	categories, err := dbService.GetCategories(context.Background(), testUser.UserID)
 
 	assert.Nil(t, err)
 	assert.Equal(t, len(expectedCategories), len(categories))
@@ -388,7 +408,7 @@ func TestService_InsertActivity(t *testing.T) {
 		t.Fatalf("failed to prepare test db: %v", err)
 	}
 
-	err := dbService.InsertActivity(golearn.Activity{
+	err := dbService.InsertActivity(context.Background(), golearn.Activity{
 		UserID:    testUser.UserID,
 		State:     testState,
 		IsRight:   true,
@@ -422,8 +442,287 @@ func TestService_GetStatistics(t *testing.T) {
 		},
 	}
 
-	statistics, err := dbService.GetStatistics(testUser.UserID, 2019, 2, 8, 22)
+	statistics, err := dbService.GetStatistics(context.Background(), testUser.UserID, 2019, 2, 8, 22)
 
 	assert.Nil(t, err)
 	assert.Equal(t, expectedStatistics, statistics)
 }
+
+func TestService_GetReview(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	review, err := dbService.GetReview(context.Background(), testUser.UserID, testWords[0])
+
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.NewReview(testUser.UserID, testWords[0]), review)
+}
+
+func TestService_SetReview(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	answers := []bool{true, true, false, true}
+	expectedDue := []time.Time{
+		time.Date(2019, 2, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 2, 21, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 2, 22, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 2, 23, 0, 0, 0, 0, time.UTC),
+	}
+
+	now := time.Date(2019, 2, 14, 0, 0, 0, 0, time.UTC)
+	for i, isRight := range answers {
+		review, err := dbService.GetReview(context.Background(), testUser.UserID, testWords[0])
+		assert.Nil(t, err)
+
+		activity := golearn.Activity{
+			UserID:    testUser.UserID,
+			IsRight:   isRight,
+			Timestamp: now,
+		}
+
+		err = dbService.SetReview(context.Background(), review.Schedule(activity.Quality(), activity.Timestamp))
+		assert.Nil(t, err)
+
+		review, err = dbService.GetReview(context.Background(), testUser.UserID, testWords[0])
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDue[i], review.Due.UTC())
+
+		now = review.Due
+	}
+}
+
+func TestService_NextDueQuestion(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	now := time.Date(2019, 2, 14, 0, 0, 0, 0, time.UTC)
+
+	// all words of the category are reviewed, the first one is overdue
+	for i, word := range testWords[:4] {
+		review := golearn.NewReview(testUser.UserID, word).Schedule(golearn.QualityRight, now)
+		if i == 0 {
+			review.Due = now.AddDate(0, 0, -1)
+		}
+		err := dbService.SetReview(context.Background(), review)
+		assert.Nil(t, err)
+	}
+
+	question, err := dbService.NextDueQuestion(context.Background(), testUser.UserID, "category", now, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, testWords[0], question)
+
+	// nothing is due, the word user has never seen is returned
+	err = dbService.SetReview(context.Background(), golearn.NewReview(testUser.UserID, testWords[0]).Schedule(golearn.QualityRight, now))
+	assert.Nil(t, err)
+
+	question, err = dbService.NextDueQuestion(context.Background(), testUser.UserID, "", now, nil)
+
+	assert.Nil(t, err)
+	assert.Contains(t, []golearn.Row{testWords[4], testWords[5]}, question)
+
+	// everything is reviewed, the closest review is returned
+	question, err = dbService.NextDueQuestion(context.Background(), testUser.UserID, "category", now, nil)
+
+	assert.Nil(t, err)
+	assert.Contains(t, testWords[:4], question)
+}
+
+func TestService_GetBoxes(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	now := time.Date(2019, 2, 14, 0, 0, 0, 0, time.UTC)
+
+	for i, word := range testWords[:4] {
+		review := golearn.NewReview(testUser.UserID, word).Leitner(i%2 == 0, now)
+		err := dbService.SetReview(context.Background(), review)
+		assert.Nil(t, err)
+	}
+
+	// reviews scheduled by SM-2 are not in any box
+	err := dbService.SetReview(context.Background(), golearn.NewReview(testUser.UserID, testWords[4]).Schedule(golearn.QualityRight, now))
+	assert.Nil(t, err)
+
+	expectedBoxes := []golearn.Box{
+		{
+			Number: 1,
+			Words:  2,
+		},
+		{
+			Number: 2,
+			Words:  2,
+		},
+	}
+
+	boxes, err := dbService.GetBoxes(context.Background(), testUser.UserID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedBoxes, boxes)
+}
+
+func TestService_SetUserDirection(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	err := dbService.SetUserDirection(context.Background(), testUser.UserID, golearn.DirectionMixed)
+
+	assert.Nil(t, err)
+
+	user, err := dbService.GetUser(context.Background(), testUser.UserID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, golearn.DirectionMixed, user.Direction)
+}
+
+func TestService_SetUserLatinInput(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	err := dbService.SetUserLatinInput(context.Background(), testUser.UserID, true)
+
+	assert.Nil(t, err)
+
+	user, err := dbService.GetUser(context.Background(), testUser.UserID)
+
+	assert.Nil(t, err)
+	assert.True(t, user.LatinInput)
+}
+
+func TestService_GetCategoriesWithOwnCollections(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	own := golearn.NewRow("가다", "идти", "my verbs")
+	own.Owner = testUser.UserID
+
+	foreign := golearn.NewRow("오다", "приходить", "other verbs")
+	foreign.Owner = "100"
+
+	assert.Nil(t, dbService.InsertWord(context.Background(), own))
+	assert.Nil(t, dbService.InsertWord(context.Background(), foreign))
+
+	expectedCategories := []golearn.Category{
+		{
+			Name:  "category",
+			Words: 4,
+		},
+		{
+			Name:  "category 2",
+			Words: 1,
+		},
+		{
+			Name:  "my verbs",
+			Words: 1,
+		},
+	}
+
+	categories, err := dbService.GetCategories(context.Background(), testUser.UserID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedCategories, categories)
+}
+
+func TestService_RandomQuestionOfOwnCollection(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	own := golearn.NewRow("가다", "идти", "verbs")
+	own.Owner = testUser.UserID
+
+	foreign := golearn.NewRow("오다", "приходить", "verbs")
+	foreign.Owner = "100"
+
+	assert.Nil(t, dbService.InsertWord(context.Background(), own))
+	assert.Nil(t, dbService.InsertWord(context.Background(), foreign))
+
+	for i := 0; i < 10; i++ {
+		question, err := dbService.RandomQuestion(context.Background(), testUser.UserID, "verbs", nil)
+
+		assert.Nil(t, err)
+		assert.Equal(t, own, question)
+
+		answers, err := dbService.RandomAnswers(context.Background(), testUser.UserID, question, 4)
+
+		assert.Nil(t, err)
+		assert.NotContains(t, answers, foreign)
+	}
+}
+
+func TestService_DeleteWordsByCategory(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	own := golearn.NewRow("가다", "идти", "category")
+	own.Owner = testUser.UserID
+
+	assert.Nil(t, dbService.InsertWord(context.Background(), own))
+
+	// global words are deleted, user own collection is kept
+	err := dbService.DeleteWordsByCategory(context.Background(), "", "category")
+
+	assert.Nil(t, err)
+
+	categories, err := dbService.GetCategories(context.Background(), testUser.UserID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []golearn.Category{
+		{
+			Name:  "category",
+			Words: 1,
+		},
+		{
+			Name:  "category 2",
+			Words: 1,
+		},
+	}, categories)
+}
+
+func TestService_ResetState(t *testing.T) {
+	if err := prepare(true); err != nil {
+		t.Fatalf("failed to prepare test db: %v", err)
+	}
+
+	assert.Nil(t, dbService.SetState(context.Background(), testState))
+
+	err := dbService.ResetState(context.Background(), testUser.UserID)
+
+	assert.Nil(t, err)
+
+	_, err = dbService.GetState(context.Background(), testUser.UserID)
+
+	assert.NotNil(t, err)
+}
+
+func TestService_Conformance(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) golearn.DBService {
+		cfg := &golearn.Config{}
+
+		cfg.Database.Host = "127.0.0.1"
+		cfg.Database.Port = "27017"
+		cfg.Database.Name = "test_conformance"
+		cfg.Database.ConnectRetries = 1
+
+		service, err := New(cfg)
+		if err != nil {
+			t.Fatalf("failed to create test db: %v", err)
+		}
+
+		err = service.db.Drop(context.Background())
+		if err != nil {
+			t.Fatalf("failed to drop test db: %v", err)
+		}
+
+		return service
+	})
+}
@@ -2,97 +2,85 @@ package mysql
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
-	"log"
+	"strings"
+	"time"
 
+	// registers mysql driver for database/sql
+	_ "github.com/go-sql-driver/mysql"
 	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/sqldb"
 )
 
-// DBService mysql database struct.
-type DBService struct {
-	DB *sql.DB
+// Dialect of mysql database. Times are kept in DATETIME columns in UTC.
+var Dialect = sqldb.Dialect{
+	Name:         "mysql",
+	Random:       "RAND()",
+	InsertIgnore: "INSERT IGNORE",
+	Upsert: func(key []string, columns []string) string {
+		set := make([]string, len(columns))
+		for i, c := range columns {
+			set[i] = c + " = VALUES(" + c + ")"
+		}
+
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+	},
+	Time: func(t time.Time) interface{} {
+		return t.UTC()
+	},
+	Day: "YEAR(timestamp) = ? AND MONTH(timestamp) = ? AND DAYOFMONTH(timestamp) = ?",
+	// mode 3 is ISO 8601 year and week, the same as time.Time.ISOWeek returns
+	Week:  "YEARWEEK(timestamp, 3) = ? * 100 + ?",
+	Month: "YEAR(timestamp) = ? AND MONTH(timestamp) = ?",
 }
 
-// Open returns opened mysql connection object.
-func Open(connection string) (*sql.DB, error) {
-	db, err := sql.Open("mysql", connection)
+// New returns new instance of service. Database schema is created if it doesn't exist yet.
+func New(cfg *golearn.Config) (*sqldb.Service, error) {
+	db, err := Open(DSN(cfg))
 	if err != nil {
 		return nil, err
 	}
-	return db, nil
-}
-
-// Exists returns boolean if row exists in table.
-func Exists(DB *sql.DB, sql string, args []interface{}) bool {
-	var exists bool
-
-	sql = fmt.Sprintf("SELECT exists (%s)", sql)
-
-	err := DB.QueryRow(sql, args...).Scan(&exists)
 
+	err = CreateSchema(db)
 	if err != nil {
-		log.Fatal(err)
+		_ = db.Close()
+		return nil, err
 	}
 
-	return exists
+	return sqldb.New(db, Dialect, cfg.Database.CallTimeout()), nil
 }
 
-// RandomRow returns random row from database.
-func (s *DBService) RandomRow() (golearn.Row, error) {
-	q := golearn.Row{}
-
-	s.DB.QueryRow("SELECT id, word, translate FROM words ORDER BY RAND() LIMIT 1").Scan(&q.ID, &q.Word, &q.Translate)
-
-	return q, nil
+// NewWithDB returns instance of service which uses passed database.
+// Schema has to be created by caller, calls are limited by context of caller only.
+func NewWithDB(db *sql.DB) *sqldb.Service {
+	return sqldb.New(db, Dialect, 0)
 }
 
-// RandomAnswers returns list of random rows for building answer options.
-func (s *DBService) RandomAnswers(q golearn.Row, limit int) ([]golearn.Row, error) {
-	fmt.Printf("%+v", q)
-	answers := []golearn.Row{}
-
-	rows, err := s.DB.Query(`
-	(SELECT id, word, translate FROM words WHERE id <> ? ORDER BY RAND() LIMIT ?)
-	UNION ALL
-	(SELECT id, word, translate FROM words WHERE id = ?)
-	ORDER BY RAND()
-	`, q.ID, limit-1, q.ID)
-
-	if err != nil {
-		return answers, err
-	}
-
-	defer rows.Close()
-
-	for rows.Next() {
-		a := golearn.Row{}
-		err := rows.Scan(&a.ID, &a.Word, &a.Translate)
-		if err != nil {
-			return answers, err
-		}
-		answers = append(answers, a)
+// DSN returns data source name of database from config.
+// Times are kept in UTC and parsed to time.Time.
+func DSN(cfg *golearn.Config) string {
+	credentials := cfg.Database.User
+	if cfg.Database.Password != "" {
+		credentials += ":" + cfg.Database.Password
 	}
 
-	return answers, nil
+	return fmt.Sprintf("%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true&loc=UTC",
+		credentials, cfg.Database.Host, cfg.Database.Port, cfg.Database.Name)
 }
 
-// SetState sets state.
-func (s *DBService) SetState(state golearn.State) error {
-	return nil
-}
-
-// GetState returns state by passed user id.
-func (s *DBService) GetState(userKey string) (golearn.State, error) {
-	return golearn.State{}, nil
-}
+// Open returns opened mysql connection object.
+// Connection is checked, so startup fails early if database isn't reachable.
+func Open(connection string) (*sql.DB, error) {
+	db, err := sql.Open("mysql", connection)
+	if err != nil {
+		return nil, err
+	}
 
-// ResetState resets state by passed user id.
-func (s *DBService) ResetState(userKey string) error {
-	if userKey == "" {
-		return errors.New("user key is empty")
+	err = db.Ping()
+	if err != nil {
+		_ = db.Close()
+		return nil, err
 	}
 
-	_, err := s.DB.Exec("UPDATE users SET last_word_id=0, last_word_set='' WHERE key=?", userKey)
-	return err
+	return db, nil
 }
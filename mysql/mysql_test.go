@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/dbtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests are run against database from MYSQL_TEST_DSN environment variable,
+// e.g. root@tcp(127.0.0.1:3306)/test?parseTime=true&loc=UTC.
+// All tables of the database are truncated.
+func TestService(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	db, err := Open(dsn)
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+
+	err = CreateSchema(db)
+	_ = db.Close()
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	dbtest.Run(t, func(t *testing.T) golearn.DBService {
+		db, err := Open(dsn)
+		if err != nil {
+			t.Fatalf("failed to open test db: %v", err)
+		}
+
+		for _, table := range []string{"words", "users", "states", "activities", "reviews"} {
+			_, err = db.Exec("TRUNCATE TABLE " + table)
+			if err != nil {
+				t.Fatalf("failed to truncate %s table: %v", table, err)
+			}
+		}
+
+		return NewWithDB(db)
+	})
+}
+
+func TestDSN(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = "3306"
+	cfg.Database.Name = "golearn"
+	cfg.Database.User = "golearn"
+
+	assert.Equal(t, "golearn@tcp(127.0.0.1:3306)/golearn?charset=utf8mb4&parseTime=true&loc=UTC", DSN(cfg))
+
+	cfg.Database.Password = "secret"
+
+	assert.Equal(t, "golearn:secret@tcp(127.0.0.1:3306)/golearn?charset=utf8mb4&parseTime=true&loc=UTC", DSN(cfg))
+}
+
+func TestInsertActivityWithLongAnswer(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	db, err := Open(dsn)
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+
+	err = CreateSchema(db)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	service := NewWithDB(db)
+	defer service.Close()
+
+	answer := strings.Repeat("단어", 500)
+
+	err = service.InsertActivity(context.Background(), golearn.Activity{
+		UserID:    "long answer user",
+		Answer:    answer,
+		Timestamp: time.Now(),
+	})
+	assert.Nil(t, err)
+
+	var saved string
+	err = db.QueryRow("SELECT answer FROM activities WHERE user_id = ?", "long answer user").Scan(&saved)
+	assert.Nil(t, err)
+	assert.Equal(t, answer, saved)
+}
@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/sergeiten/golearn/sqldb"
+)
+
+// schema creates tables used by Service. Statements can be run many times,
+// existing tables are kept as is. Changes of existing tables are made by migrations.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS words (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+		word VARCHAR(255) NOT NULL,
+		translate VARCHAR(255) NOT NULL,
+		category VARCHAR(255) NOT NULL DEFAULT '',
+		spellings TEXT NULL,
+		translations TEXT NULL,
+		owner VARCHAR(64) NOT NULL DEFAULT '',
+		PRIMARY KEY (id),
+		KEY words_category (category),
+		KEY words_owner_category (owner, category),
+		KEY words_word (word)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS users (
+		user_id VARCHAR(64) NOT NULL,
+		username VARCHAR(255) NOT NULL DEFAULT '',
+		name VARCHAR(255) NOT NULL DEFAULT '',
+		mode VARCHAR(32) NOT NULL DEFAULT '',
+		category VARCHAR(255) NOT NULL DEFAULT '',
+		direction VARCHAR(32) NOT NULL DEFAULT '',
+		latin_input TINYINT(1) NOT NULL DEFAULT 0,
+		PRIMARY KEY (user_id)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS states (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+		user_key VARCHAR(64) NOT NULL,
+		timestamp BIGINT NOT NULL,
+		data MEDIUMTEXT NOT NULL,
+		PRIMARY KEY (id),
+		KEY states_user_key_timestamp (user_key, timestamp)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS activities (
+		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+		user_id VARCHAR(64) NOT NULL,
+		answer TEXT NOT NULL,
+		is_right TINYINT(1) NOT NULL,
+		verdict VARCHAR(16) NOT NULL DEFAULT '',
+		similarity DOUBLE NOT NULL DEFAULT 0,
+		state MEDIUMTEXT NOT NULL,
+		timestamp DATETIME(6) NOT NULL,
+		PRIMARY KEY (id),
+		KEY activities_user_id_timestamp (user_id, timestamp)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS reviews (
+		user_id VARCHAR(64) NOT NULL,
+		word VARCHAR(255) NOT NULL,
+		category VARCHAR(255) NOT NULL DEFAULT '',
+		ease_factor DOUBLE NOT NULL,
+		interval_days INT NOT NULL DEFAULT 0,
+		repetitions INT NOT NULL DEFAULT 0,
+		box INT NOT NULL DEFAULT 0,
+		due DATETIME(6) NOT NULL,
+		PRIMARY KEY (user_id, word, category),
+		KEY reviews_user_id_due (user_id, due)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+	`CREATE TABLE IF NOT EXISTS migrations (
+		version INT NOT NULL,
+		description VARCHAR(255) NOT NULL DEFAULT '',
+		applied_at DATETIME(6) NOT NULL,
+		PRIMARY KEY (version)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+}
+
+// migrations are applied in order, version of every next migration is greater by one.
+// Applied migrations must never be changed, add new one instead.
+var migrations = []sqldb.Migration{
+	{
+		Version:     1,
+		Description: "add answers count of users",
+		Up: func(db *sql.DB) error {
+			return sqldb.AddColumn(db, "users", "answers", "INT NOT NULL DEFAULT 0")
+		},
+	},
+	{
+		Version:     2,
+		Description: "keep answers of any length",
+		Up: func(db *sql.DB) error {
+			_, err := db.Exec("ALTER TABLE activities MODIFY answer TEXT NOT NULL")
+			return err
+		},
+	},
+}
+
+// CreateSchema creates tables of the bot in passed database if they don't exist
+// and applies migrations which haven't been applied yet.
+func CreateSchema(db *sql.DB) error {
+	return sqldb.CreateSchema(db, Dialect, schema, migrations)
+}
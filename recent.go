@@ -0,0 +1,34 @@
+package golearn
+
+// DefaultRecentWords is count of recently asked words which aren't asked again if it's not configured.
+const DefaultRecentWords = 5
+
+// ExcludeRecent calls ask with recently asked words which have to be skipped.
+// If there is no other word ask is called again without skipping them,
+// so words of categories smaller than the window are still asked.
+func ExcludeRecent(recent []string, ask func(exclude []string) (Row, error)) (Row, error) {
+	row, err := ask(recent)
+	if err == ErrNoWords && len(recent) > 0 {
+		return ask(nil)
+	}
+
+	return row, err
+}
+
+// StateWords returns words asked in passed states without repeats keeping their order.
+// States of adding word to own collection are skipped, their word isn't asked.
+func StateWords(states []State) []string {
+	var words []string
+	seen := map[string]bool{}
+
+	for _, s := range states {
+		if s.Step != "" || s.Question.Word == "" || seen[s.Question.Word] {
+			continue
+		}
+
+		seen[s.Question.Word] = true
+		words = append(words, s.Question.Word)
+	}
+
+	return words
+}
@@ -0,0 +1,80 @@
+package golearn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcludeRecent(t *testing.T) {
+	row := Row{Word: "가다", Translate: "идти"}
+	sampleError := errors.New("sample error")
+
+	testCases := map[string]struct {
+		Recent   []string
+		Results  []error
+		Expected [][]string
+		Error    error
+	}{
+		"other word": {
+			Recent:   []string{"오다"},
+			Results:  []error{nil},
+			Expected: [][]string{{"오다"}},
+			Error:    nil,
+		},
+		"only recent words": {
+			Recent:   []string{"오다", "가다"},
+			Results:  []error{ErrNoWords, nil},
+			Expected: [][]string{{"오다", "가다"}, nil},
+			Error:    nil,
+		},
+		"no words": {
+			Recent:   nil,
+			Results:  []error{ErrNoWords},
+			Expected: [][]string{nil},
+			Error:    ErrNoWords,
+		},
+		"error": {
+			Recent:   []string{"오다"},
+			Results:  []error{sampleError},
+			Expected: [][]string{{"오다"}},
+			Error:    sampleError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var calls [][]string
+
+			r, err := ExcludeRecent(tc.Recent, func(exclude []string) (Row, error) {
+				result := tc.Results[len(calls)]
+				calls = append(calls, exclude)
+				if result != nil {
+					return Row{}, result
+				}
+				return row, nil
+			})
+
+			assert.Equal(t, tc.Expected, calls)
+			assert.Equal(t, tc.Error, err)
+			if tc.Error == nil {
+				assert.Equal(t, row, r)
+			}
+		})
+	}
+}
+
+func TestStateWords(t *testing.T) {
+	states := []State{
+		{Question: Row{Word: "가다"}},
+		{Question: Row{Word: "오다"}},
+		{Question: Row{Word: "가다"}},
+		{Question: Row{Word: "집"}, Step: StepTranslate},
+		{},
+		{Question: Row{Word: "보다"}},
+	}
+
+	assert.Equal(t, []string{"가다", "오다", "보다"}, StateWords(states))
+	assert.Nil(t, StateWords(nil))
+}
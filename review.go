@@ -0,0 +1,131 @@
+package golearn
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultEaseFactor is initial ease factor of word which has never been reviewed.
+const DefaultEaseFactor = 2.5
+
+// MinEaseFactor is the lowest ease factor allowed by SM-2 algorithm.
+const MinEaseFactor = 1.3
+
+// QualityRight is SM-2 quality of right answer.
+const QualityRight = 4
+
+// QualityClose is SM-2 quality of right answer with a few typos.
+const QualityClose = 3
+
+// QualityWrong is SM-2 quality of wrong answer.
+const QualityWrong = 1
+
+// LeitnerBoxes is number of boxes in Leitner mode.
+const LeitnerBoxes = 5
+
+// Review represents user review schedule of a word calculated by SM-2 algorithm
+// or by Leitner system, depending on user mode. Review belongs to the mode which
+// scheduled it last, fields of other mode are reset, so due date is always set by
+// fields kept in review. Word is in Leitner box only if Box isn't zero.
+// Word is used as key instead of row id, because words are reimported by fetcher
+// and get new ids, but text of the word stays the same.
+type Review struct {
+	UserID      string
+	Word        string
+	Category    string
+	EaseFactor  float64
+	Interval    int
+	Repetitions int
+	Box         int
+	Due         time.Time
+}
+
+// NewReview returns review of passed row which has never been reviewed by user.
+func NewReview(userID string, row Row) Review {
+	return Review{
+		UserID:     userID,
+		Word:       row.Word,
+		Category:   row.Category,
+		EaseFactor: DefaultEaseFactor,
+	}
+}
+
+// Schedule returns review rescheduled by SM-2 algorithm.
+// quality is grade of the answer from 0 (total blackout) to 5 (perfect response),
+// now is time of the answer. Interval is calculated in days.
+func (r Review) Schedule(quality int, now time.Time) Review {
+	// word leaves Leitner box after switch of mode
+	r.Box = 0
+
+	if quality >= 3 {
+		switch r.Repetitions {
+		case 0:
+			r.Interval = 1
+		case 1:
+			r.Interval = 6
+		default:
+			r.Interval = int(math.Round(float64(r.Interval) * r.EaseFactor))
+		}
+		r.Repetitions++
+	} else {
+		r.Repetitions = 0
+		r.Interval = 1
+	}
+
+	q := float64(5 - quality)
+	r.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if r.EaseFactor < MinEaseFactor {
+		r.EaseFactor = MinEaseFactor
+	}
+
+	r.Due = now.AddDate(0, 0, r.Interval)
+
+	return r
+}
+
+// Leitner returns review rescheduled by Leitner system.
+// New words are put to the first box. Right answer moves the word to the next box,
+// wrong answer moves it back to the first one. Words from box n are reviewed every
+// 2^(n-1) days, so the first box is reviewed daily and the last one every 16 days.
+func (r Review) Leitner(isRight bool, now time.Time) Review {
+	// SM-2 schedule starts again after switch of mode
+	r.EaseFactor = DefaultEaseFactor
+	r.Interval = 0
+	r.Repetitions = 0
+
+	if r.Box == 0 {
+		r.Box = 1
+	}
+
+	switch {
+	case !isRight:
+		r.Box = 1
+	case r.Box < LeitnerBoxes:
+		r.Box++
+	}
+
+	r.Due = now.AddDate(0, 0, LeitnerInterval(r.Box))
+
+	return r
+}
+
+// LeitnerInterval returns interval in days between reviews of words from passed box.
+func LeitnerInterval(box int) int {
+	if box < 1 {
+		return 0
+	}
+	return 1 << uint(box-1)
+}
+
+// Quality returns SM-2 quality of the answer saved in activity.
+// Close answer gets partial credit.
+func (a Activity) Quality() int {
+	switch {
+	case a.Verdict == VerdictClose:
+		return QualityClose
+	case a.IsRight:
+		return QualityRight
+	default:
+		return QualityWrong
+	}
+}
@@ -0,0 +1,182 @@
+package golearn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewSchedule(t *testing.T) {
+	row := Row{
+		Word:      "origin word",
+		Translate: "translated word",
+		Category:  "category",
+	}
+
+	start := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		Answers    []bool
+		Due        []time.Time
+		EaseFactor float64
+	}{
+		"right answers only": {
+			Answers: []bool{true, true, true, true},
+			Due: []time.Time{
+				time.Date(2019, 2, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 8, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 23, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 4, 2, 0, 0, 0, 0, time.UTC),
+			},
+			EaseFactor: 2.5,
+		},
+		"wrong answer resets interval": {
+			Answers: []bool{true, true, false, true, true},
+			Due: []time.Time{
+				time.Date(2019, 2, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 8, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 9, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 10, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 16, 0, 0, 0, 0, time.UTC),
+			},
+			EaseFactor: 1.96,
+		},
+		"wrong answers keep minimal ease factor": {
+			Answers: []bool{false, false, false, true, true, true},
+			Due: []time.Time{
+				time.Date(2019, 2, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 5, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 11, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 19, 0, 0, 0, 0, time.UTC),
+			},
+			EaseFactor: MinEaseFactor,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			review := NewReview("177374215", row)
+
+			// every answer is given on the day the word is due
+			now := start
+			for i, isRight := range tc.Answers {
+				activity := Activity{
+					UserID:    review.UserID,
+					IsRight:   isRight,
+					Timestamp: now,
+				}
+
+				review = review.Schedule(activity.Quality(), activity.Timestamp)
+
+				assert.Equal(t, tc.Due[i], review.Due, "answer #%d", i)
+
+				now = review.Due
+			}
+
+			assert.InDelta(t, tc.EaseFactor, review.EaseFactor, 0.0001)
+		})
+	}
+}
+
+func TestReviewLeitner(t *testing.T) {
+	row := Row{
+		Word:      "origin word",
+		Translate: "translated word",
+		Category:  "category",
+	}
+
+	start := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		Answers []bool
+		Boxes   []int
+		Due     []time.Time
+	}{
+		"right answers move word up to the last box": {
+			Answers: []bool{true, true, true, true, true},
+			Boxes:   []int{2, 3, 4, 5, 5},
+			Due: []time.Time{
+				time.Date(2019, 2, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 7, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 3, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 3, 19, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		"wrong answer moves word back to the first box": {
+			Answers: []bool{false, true, true, false, true},
+			Boxes:   []int{1, 2, 3, 1, 2},
+			Due: []time.Time{
+				time.Date(2019, 2, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 8, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 9, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, 2, 11, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			review := NewReview("177374215", row)
+
+			now := start
+			for i, isRight := range tc.Answers {
+				review = review.Leitner(isRight, now)
+
+				assert.Equal(t, tc.Boxes[i], review.Box, "answer #%d", i)
+				assert.Equal(t, tc.Due[i], review.Due, "answer #%d", i)
+
+				now = review.Due
+			}
+		})
+	}
+}
+
+func TestReviewModeSwitch(t *testing.T) {
+	row := Row{
+		Word:      "origin word",
+		Translate: "translated word",
+		Category:  "category",
+	}
+
+	steps := []struct {
+		Leitner     bool
+		Box         int
+		Repetitions int
+		Interval    int
+		Due         time.Time
+	}{
+		{Leitner: false, Box: 0, Repetitions: 1, Interval: 1, Due: time.Date(2019, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{Leitner: false, Box: 0, Repetitions: 2, Interval: 6, Due: time.Date(2019, 2, 8, 0, 0, 0, 0, time.UTC)},
+		// SM-2 progress is reset, word is put to the first box and moved to the second one
+		{Leitner: true, Box: 2, Repetitions: 0, Interval: 0, Due: time.Date(2019, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{Leitner: true, Box: 3, Repetitions: 0, Interval: 0, Due: time.Date(2019, 2, 14, 0, 0, 0, 0, time.UTC)},
+		// word leaves Leitner box and SM-2 schedule starts again
+		{Leitner: false, Box: 0, Repetitions: 1, Interval: 1, Due: time.Date(2019, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{Leitner: true, Box: 2, Repetitions: 0, Interval: 0, Due: time.Date(2019, 2, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	review := NewReview("177374215", row)
+
+	// every answer is right and given on the day the word is due
+	now := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i, step := range steps {
+		if step.Leitner {
+			review = review.Leitner(true, now)
+		} else {
+			review = review.Schedule(QualityRight, now)
+		}
+
+		assert.Equal(t, step.Box, review.Box, "answer #%d", i)
+		assert.Equal(t, step.Repetitions, review.Repetitions, "answer #%d", i)
+		assert.Equal(t, step.Interval, review.Interval, "answer #%d", i)
+		assert.Equal(t, step.Due, review.Due, "answer #%d", i)
+		assert.Equal(t, DefaultEaseFactor, review.EaseFactor, "answer #%d", i)
+
+		now = review.Due
+	}
+}
@@ -0,0 +1,293 @@
+package golearn
+
+import (
+	"strings"
+	"unicode"
+)
+
+// dubeolsik maps keys of Latin keyboard to jamo of standard Korean keyboard layout.
+var dubeolsik = map[rune]rune{
+	'q': 'ㅂ', 'w': 'ㅈ', 'e': 'ㄷ', 'r': 'ㄱ', 't': 'ㅅ', 'y': 'ㅛ', 'u': 'ㅕ', 'i': 'ㅑ', 'o': 'ㅐ', 'p': 'ㅔ',
+	'a': 'ㅁ', 's': 'ㄴ', 'd': 'ㅇ', 'f': 'ㄹ', 'g': 'ㅎ', 'h': 'ㅗ', 'j': 'ㅓ', 'k': 'ㅏ', 'l': 'ㅣ',
+	'z': 'ㅋ', 'x': 'ㅌ', 'c': 'ㅊ', 'v': 'ㅍ', 'b': 'ㅠ', 'n': 'ㅜ', 'm': 'ㅡ',
+	'Q': 'ㅃ', 'W': 'ㅉ', 'E': 'ㄸ', 'R': 'ㄲ', 'T': 'ㅆ', 'O': 'ㅒ', 'P': 'ㅖ',
+}
+
+// compoundVowels maps pair of vowels typed one by one to compound vowel.
+var compoundVowels = map[[2]rune]rune{
+	{'ㅗ', 'ㅏ'}: 'ㅘ', {'ㅗ', 'ㅐ'}: 'ㅙ', {'ㅗ', 'ㅣ'}: 'ㅚ',
+	{'ㅜ', 'ㅓ'}: 'ㅝ', {'ㅜ', 'ㅔ'}: 'ㅞ', {'ㅜ', 'ㅣ'}: 'ㅟ',
+	{'ㅡ', 'ㅣ'}: 'ㅢ',
+}
+
+// compoundFinals maps pair of consonants typed one by one to double batchim.
+var compoundFinals = map[[2]rune]rune{
+	{'ㄱ', 'ㅅ'}: 'ㄳ', {'ㄴ', 'ㅈ'}: 'ㄵ', {'ㄴ', 'ㅎ'}: 'ㄶ',
+	{'ㄹ', 'ㄱ'}: 'ㄺ', {'ㄹ', 'ㅁ'}: 'ㄻ', {'ㄹ', 'ㅂ'}: 'ㄼ', {'ㄹ', 'ㅅ'}: 'ㄽ',
+	{'ㄹ', 'ㅌ'}: 'ㄾ', {'ㄹ', 'ㅍ'}: 'ㄿ', {'ㄹ', 'ㅎ'}: 'ㅀ', {'ㅂ', 'ㅅ'}: 'ㅄ',
+}
+
+// romanVowels is Revised Romanization of vowels, longer spellings go first.
+var romanVowels = []struct {
+	Latin string
+	Jamo  rune
+}{
+	{"yae", 'ㅒ'}, {"yeo", 'ㅕ'}, {"wae", 'ㅙ'},
+	{"ya", 'ㅑ'}, {"ye", 'ㅖ'}, {"yo", 'ㅛ'}, {"yu", 'ㅠ'},
+	{"wa", 'ㅘ'}, {"wo", 'ㅝ'}, {"we", 'ㅞ'}, {"wi", 'ㅟ'},
+	{"ae", 'ㅐ'}, {"eo", 'ㅓ'}, {"eu", 'ㅡ'}, {"oe", 'ㅚ'}, {"ui", 'ㅢ'},
+	{"a", 'ㅏ'}, {"e", 'ㅔ'}, {"i", 'ㅣ'}, {"o", 'ㅗ'}, {"u", 'ㅜ'},
+}
+
+// romanInitials is Revised Romanization of initial consonants.
+var romanInitials = map[string]rune{
+	"g": 'ㄱ', "kk": 'ㄲ', "n": 'ㄴ', "d": 'ㄷ', "tt": 'ㄸ', "r": 'ㄹ', "l": 'ㄹ', "m": 'ㅁ', "b": 'ㅂ',
+	"pp": 'ㅃ', "s": 'ㅅ', "ss": 'ㅆ', "j": 'ㅈ', "jj": 'ㅉ', "ch": 'ㅊ', "k": 'ㅋ', "t": 'ㅌ', "p": 'ㅍ', "h": 'ㅎ',
+}
+
+// romanFinals is Revised Romanization of final consonants.
+// Unreleased finals are romanized as k, t, p, but learners often type them as written.
+var romanFinals = map[string]rune{
+	"k": 'ㄱ', "g": 'ㄱ', "kk": 'ㄲ', "n": 'ㄴ', "t": 'ㄷ', "d": 'ㄷ', "l": 'ㄹ', "r": 'ㄹ', "m": 'ㅁ', "p": 'ㅂ', "b": 'ㅂ',
+	"s": 'ㅅ', "ss": 'ㅆ', "ng": 'ㅇ', "j": 'ㅈ', "ch": 'ㅊ', "h": 'ㅎ',
+}
+
+// HasHangul returns true if passed string contains Hangul syllables or jamo.
+func HasHangul(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Hangul, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromDubeolsik returns text typed on Latin keyboard as if Korean layout was on.
+// E.g. "gksrmf" is converted to "한글". Characters which are not keys of
+// the layout are left as is.
+func FromDubeolsik(s string) string {
+	jamos := make([]rune, 0, len(s))
+	for _, r := range s {
+		if jamo, ok := dubeolsik[r]; ok {
+			jamos = append(jamos, jamo)
+			continue
+		}
+		if jamo, ok := dubeolsik[unicode.ToLower(r)]; ok {
+			jamos = append(jamos, jamo)
+			continue
+		}
+		jamos = append(jamos, r)
+	}
+
+	return Compose(jamos)
+}
+
+// Compose returns syllables composed from sequence of compatibility jamo the same way
+// Korean input method does. Consonant followed by vowel starts new syllable, so batchim
+// typed before vowel moves to the next syllable.
+func Compose(jamos []rune) string {
+	var b strings.Builder
+
+	for i := 0; i < len(jamos); {
+		initial := jamos[i]
+
+		if !isInitial(initial) || i+1 >= len(jamos) || !isMedial(jamos[i+1]) {
+			// standalone vowel can be compound one as well
+			if isMedial(initial) && i+1 < len(jamos) {
+				if vowel, ok := compoundVowels[[2]rune{initial, jamos[i+1]}]; ok {
+					b.WriteRune(vowel)
+					i += 2
+					continue
+				}
+			}
+			b.WriteRune(initial)
+			i++
+			continue
+		}
+
+		medial := jamos[i+1]
+		i += 2
+
+		if i < len(jamos) {
+			if vowel, ok := compoundVowels[[2]rune{medial, jamos[i]}]; ok {
+				medial = vowel
+				i++
+			}
+		}
+
+		var final rune
+		if i < len(jamos) && isFinal(jamos[i]) && !startsSyllable(jamos, i) {
+			final = jamos[i]
+			i++
+
+			if i < len(jamos) && !startsSyllable(jamos, i) {
+				if double, ok := compoundFinals[[2]rune{final, jamos[i]}]; ok {
+					final = double
+					i++
+				}
+			}
+		}
+
+		b.WriteRune(syllable(initial, medial, final))
+	}
+
+	return b.String()
+}
+
+// FromRomanization returns Hangul spelling of text written in Revised Romanization,
+// e.g. "hangeul" is converted to "한글". Romanization is phonetic, so words with
+// sound changes come out as they are pronounced ("hamnida" is "함니다").
+// Hyphen separates syllables ("jung-ang" is "중앙") and is removed.
+func FromRomanization(s string) string {
+	var b strings.Builder
+
+	word := []rune{}
+	flush := func() {
+		b.WriteString(romanWord(string(word)))
+		word = word[:0]
+	}
+
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			word = append(word, r)
+			continue
+		}
+
+		flush()
+		if r != '-' {
+			b.WriteRune(r)
+		}
+	}
+	flush()
+
+	return b.String()
+}
+
+// romanWord converts single word of Latin letters to Hangul.
+func romanWord(word string) string {
+	// split word to consonant clusters and vowels: c0 v1 c1 v2 c2 ... vn cn
+	var clusters []string
+	var vowels []rune
+
+	cluster := ""
+	for i := 0; i < len(word); {
+		matched := false
+		for _, v := range romanVowels {
+			if strings.HasPrefix(word[i:], v.Latin) {
+				clusters = append(clusters, cluster)
+				vowels = append(vowels, v.Jamo)
+				cluster = ""
+				i += len(v.Latin)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			cluster += word[i : i+1]
+			i++
+		}
+	}
+	clusters = append(clusters, cluster)
+
+	if len(vowels) == 0 {
+		return word
+	}
+
+	var b strings.Builder
+
+	initial, _ := splitCluster(clusters[0])
+	for i, vowel := range vowels {
+		next, final := rune(0), rune(0)
+		if i+1 < len(vowels) {
+			var rest string
+			next, rest = splitCluster(clusters[i+1])
+			final = romanFinal(rest)
+		} else {
+			final = romanFinal(clusters[i+1])
+		}
+
+		b.WriteRune(syllable(initial, vowel, final))
+		initial = next
+	}
+
+	return b.String()
+}
+
+// splitCluster returns initial consonant of the next syllable, which is the longest
+// romanized initial at the end of cluster, and the rest of cluster which belongs
+// to the previous syllable. Syllable without initial consonant starts with ㅇ.
+func splitCluster(cluster string) (rune, string) {
+	for _, size := range []int{2, 1} {
+		if len(cluster) < size {
+			continue
+		}
+		if initial, ok := romanInitials[cluster[len(cluster)-size:]]; ok {
+			return initial, cluster[:len(cluster)-size]
+		}
+	}
+
+	return 'ㅇ', cluster
+}
+
+// romanFinal returns batchim written as passed cluster, double batchim is supported.
+func romanFinal(cluster string) rune {
+	var final rune
+
+	for len(cluster) > 0 {
+		size := 1
+		if len(cluster) > 1 {
+			if _, ok := romanFinals[cluster[:2]]; ok {
+				size = 2
+			}
+		}
+
+		jamo, ok := romanFinals[cluster[:size]]
+		cluster = cluster[size:]
+		if !ok {
+			continue
+		}
+
+		if final == 0 {
+			final = jamo
+			continue
+		}
+
+		if double, ok := compoundFinals[[2]rune{final, jamo}]; ok {
+			final = double
+		}
+	}
+
+	return final
+}
+
+// syllable returns precomposed syllable of passed jamo, final is zero for syllable without batchim.
+func syllable(initial rune, medial rune, final rune) rune {
+	return syllableBase + rune(indexOf(initials, initial)*syllableBlock+indexOf(medials, medial)*finalsCount+indexOf(finals, final))
+}
+
+// startsSyllable returns true if consonant at position i is followed by vowel.
+func startsSyllable(jamos []rune, i int) bool {
+	return isInitial(jamos[i]) && i+1 < len(jamos) && isMedial(jamos[i+1])
+}
+
+func isInitial(r rune) bool {
+	return indexOf(initials, r) >= 0
+}
+
+func isMedial(r rune) bool {
+	return indexOf(medials, r) >= 0
+}
+
+func isFinal(r rune) bool {
+	return r != 0 && indexOf(finals, r) > 0
+}
+
+func indexOf(runes []rune, r rune) int {
+	for i, v := range runes {
+		if v == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,64 @@
+package golearn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromDubeolsik(t *testing.T) {
+	testCases := map[string]struct {
+		Input    string
+		Expected string
+	}{
+		"simple syllables":                            {Input: "gksrmf", Expected: "한글"},
+		"batchim moves to the next syllable":          {Input: "dkssudgktpdy", Expected: "안녕하세요"},
+		"compound vowel":                              {Input: "rhkdls", Expected: "과인"},
+		"compound vowel without initial":              {Input: "hk", Expected: "ㅘ"},
+		"double batchim":                              {Input: "dlfr", Expected: "읽"},
+		"double batchim splits before vowel":          {Input: "dlfrj", Expected: "일거"},
+		"double batchim before syllable with initial": {Input: "dlfrdjdy", Expected: "읽어요"},
+		"double consonant with shift":                 {Input: "Tkfkd", Expected: "싸랑"},
+		"shift on other keys is ignored":              {Input: "DKSSUD", Expected: "안녕"},
+		"double consonant can't be batchim":           {Input: "dkQ", Expected: "아ㅃ"},
+		"standalone jamo":                             {Input: "rr", Expected: "ㄱㄱ"},
+		"words and punctuation are kept":              {Input: "gks rmf!", Expected: "한 글!"},
+		"empty string":                                {Input: "", Expected: ""},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, FromDubeolsik(tc.Input))
+		})
+	}
+}
+
+func TestFromRomanization(t *testing.T) {
+	testCases := map[string]struct {
+		Input    string
+		Expected string
+	}{
+		"simple word":                         {Input: "hangeul", Expected: "한글"},
+		"syllables without initial consonant": {Input: "annyeonghaseyo", Expected: "안녕하세요"},
+		"ng batchim at the end":               {Input: "sarang", Expected: "사랑"},
+		"hyphen separates syllables":          {Input: "jung-ang", Expected: "중앙"},
+		"double initial consonant":            {Input: "kkot", Expected: "꼳"},
+		"compound vowel":                      {Input: "gwaja", Expected: "과자"},
+		"phonetic spelling":                   {Input: "gamsahamnida", Expected: "감사함니다"},
+		"upper case":                          {Input: "Seoul", Expected: "서울"},
+		"several words":                       {Input: "jal ja", Expected: "잘 자"},
+		"no vowels":                           {Input: "ng", Expected: "ng"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, FromRomanization(tc.Input))
+		})
+	}
+}
+
+func TestHasHangul(t *testing.T) {
+	assert.True(t, HasHangul("한글"))
+	assert.True(t, HasHangul("ㅎ"))
+	assert.False(t, HasHangul("hangeul"))
+}
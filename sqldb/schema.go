@@ -0,0 +1,71 @@
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Migration changes schema of database to the next version.
+// Up has to be safe to run again, so migration interrupted before it's recorded
+// or run by several instances at once doesn't break the database.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(db *sql.DB) error
+}
+
+// CreateSchema runs statements which create tables if they don't exist
+// and applies migrations which haven't been applied yet. Statements can be run many times,
+// existing tables are kept as is. Changes of existing tables are made by migrations.
+// Migrations table has to be created by statements.
+func CreateSchema(db *sql.DB, dialect Dialect, schema []string, migrations []Migration) error {
+	for _, statement := range schema {
+		_, err := db.Exec(statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	return migrate(db, dialect, migrations)
+}
+
+// migrate applies migrations with version greater than version of the last applied one.
+// Migrations are applied in order, version of every next migration is greater by one.
+func migrate(db *sql.DB, dialect Dialect, migrations []Migration) error {
+	var version int
+	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM migrations").Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+
+		err = m.Up(db)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Exec(dialect.InsertIgnore+" INTO migrations (version, description, applied_at) VALUES (?, ?, ?)",
+			m.Version, m.Description, dialect.Time(time.Now()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddColumn adds column to the table if there is no such column yet.
+func AddColumn(db *sql.DB, table string, column string, definition string) error {
+	rows, err := db.Query("SELECT " + column + " FROM " + table + " LIMIT 0")
+	if err == nil {
+		return rows.Close()
+	}
+
+	_, err = db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+
+	return err
+}
@@ -0,0 +1,647 @@
+// Package sqldb implements golearn.DBService on database/sql.
+// Databases differ by Dialect, their packages create schema and open connections.
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergeiten/golearn"
+)
+
+// wordColumns are columns of words table scanned by scanRow.
+const wordColumns = "id, word, translate, category, spellings, translations, owner"
+
+// Dialect holds SQL which differs between databases.
+type Dialect struct {
+	// Name of database used in log messages.
+	Name string
+	// Random is function which orders rows randomly.
+	Random string
+	// InsertIgnore starts insert which skips rows with existing key.
+	InsertIgnore string
+	// Upsert returns clause of insert which updates columns of existing row with the same key.
+	Upsert func(key []string, columns []string) string
+	// Time returns value of time saved to database. Times are scanned from both
+	// time.Time and unix nanoseconds.
+	Time func(t time.Time) interface{}
+	// DateParts is true if year, month, ISO week and day of activities are saved
+	// to separate columns, e.g. for database which can't get ISO week of the date.
+	DateParts bool
+	// Day, Week and Month are conditions of activities of the period. Day takes year,
+	// month and day arguments, Week takes ISO year and week, Month takes year and month.
+	Day, Week, Month string
+}
+
+// Service of database/sql database.
+type Service struct {
+	db      *sql.DB
+	dialect Dialect
+	timeout time.Duration
+}
+
+// New returns new instance of Service which uses passed database of dialect.
+// Schema has to be created by caller, zero timeout doesn't limit calls.
+func New(db *sql.DB, dialect Dialect, timeout time.Duration) *Service {
+	return &Service{
+		db:      db,
+		dialect: dialect,
+		timeout: timeout,
+	}
+}
+
+// withTimeout returns ctx limited by timeout of database call.
+func (s *Service) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// Close closes the database.
+func (s *Service) Close() {
+	err := s.db.Close()
+	golearn.LogPrint(err, "failed to close "+s.dialect.Name+" database")
+}
+
+// RandomQuestion returns random row of global words and words of passed user.
+// Words in exclude are skipped unless there is no other word.
+func (s *Service) RandomQuestion(ctx context.Context, userID string, category string, exclude []string) (golearn.Row, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return golearn.ExcludeRecent(exclude, func(exclude []string) (golearn.Row, error) {
+		query := "SELECT " + wordColumns + " FROM words WHERE owner IN ('', ?)"
+		args := []interface{}{userID}
+
+		if category != "" {
+			query += " AND category = ?"
+			args = append(args, category)
+		}
+
+		skip, skipArgs := notIn("word", exclude)
+		query += skip
+		args = append(args, skipArgs...)
+
+		row, err := s.queryRow(ctx, query+" ORDER BY "+s.dialect.Random+" LIMIT 1", args...)
+		if err == sql.ErrNoRows {
+			return row, golearn.ErrNoWords
+		}
+
+		return row, err
+	})
+}
+
+// NextDueQuestion returns the word user has to review next.
+// Word with the most overdue review goes first. If there is nothing to review
+// random word which user has never seen is returned. If user has seen all words
+// the word with the closest review date is returned.
+// Words in exclude are skipped unless there is no other word.
+func (s *Service) NextDueQuestion(ctx context.Context, userID string, category string, now time.Time, exclude []string) (golearn.Row, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return golearn.ExcludeRecent(exclude, func(exclude []string) (golearn.Row, error) {
+		return s.nextDueQuestion(ctx, userID, category, now, exclude)
+	})
+}
+
+func (s *Service) nextDueQuestion(ctx context.Context, userID string, category string, now time.Time, exclude []string) (golearn.Row, error) {
+	condition := "user_id = ?"
+	args := []interface{}{userID}
+
+	if category != "" {
+		condition += " AND category = ?"
+		args = append(args, category)
+	}
+
+	skip, skipArgs := notIn("word", exclude)
+	condition += skip
+	args = append(args, skipArgs...)
+
+	// reviews of deleted words are skipped
+	condition += " AND EXISTS (SELECT 1 FROM words WHERE words.word = reviews.word" +
+		" AND words.category = reviews.category AND words.owner IN ('', reviews.user_id))"
+
+	dueArgs := append(append([]interface{}{}, args...), s.dialect.Time(now))
+	review, err := s.queryReview(ctx, "SELECT "+reviewColumns+" FROM reviews WHERE "+condition+" AND due <= ? ORDER BY due LIMIT 1", dueArgs...)
+	if err == nil {
+		return s.reviewRow(ctx, review)
+	}
+	if err != sql.ErrNoRows {
+		return golearn.Row{}, err
+	}
+
+	query := "SELECT " + wordColumns + " FROM words WHERE owner IN ('', ?)" +
+		" AND word NOT IN (SELECT word FROM reviews WHERE user_id = ?)"
+	unseenArgs := []interface{}{userID, userID}
+
+	if category != "" {
+		query += " AND category = ?"
+		unseenArgs = append(unseenArgs, category)
+	}
+
+	query += skip
+	unseenArgs = append(unseenArgs, skipArgs...)
+
+	row, err := s.queryRow(ctx, query+" ORDER BY "+s.dialect.Random+" LIMIT 1", unseenArgs...)
+	if err != sql.ErrNoRows {
+		return row, err
+	}
+
+	review, err = s.queryReview(ctx, "SELECT "+reviewColumns+" FROM reviews WHERE "+condition+" ORDER BY due LIMIT 1", args...)
+	if err == sql.ErrNoRows {
+		return golearn.Row{}, golearn.ErrNoWords
+	}
+	if err != nil {
+		return golearn.Row{}, err
+	}
+
+	return s.reviewRow(ctx, review)
+}
+
+func (s *Service) reviewRow(ctx context.Context, review golearn.Review) (golearn.Row, error) {
+	return s.queryRow(ctx, "SELECT "+wordColumns+" FROM words WHERE word = ? AND category = ? AND owner IN ('', ?) LIMIT 1",
+		review.Word, review.Category, review.UserID)
+}
+
+// RandomAnswers returns random answers with passed count of total answers.
+// q is right answer which is appended to result slice and will be shuffled later.
+// Wrong answers are picked by golearn.PickDistractors.
+func (s *Service) RandomAnswers(ctx context.Context, userID string, q golearn.Row, count int) ([]golearn.Row, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	r, err := golearn.PickDistractors(q, count-1, func(sameCategory bool, limit int) ([]golearn.Row, error) {
+		category := "category = ?"
+		if !sameCategory {
+			category = "category <> ?"
+		}
+
+		return s.queryRows(ctx, "SELECT "+wordColumns+" FROM words WHERE word <> ? AND owner IN ('', ?) AND "+category+" ORDER BY "+s.dialect.Random+" LIMIT ?",
+			q.Word, userID, q.Category, limit)
+	})
+	if err != nil {
+		return r, err
+	}
+
+	return append(r, q), nil
+}
+
+// SetState save latest given set of question and answers
+func (s *Service) SetState(ctx context.Context, state golearn.State) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO states (user_key, timestamp, data) VALUES (?, ?, ?)", state.UserKey, state.Timestamp, data)
+
+	return err
+}
+
+// GetState returns lastest saved user state
+func (s *Service) GetState(ctx context.Context, userKey string) (golearn.State, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	state := golearn.State{}
+
+	var data []byte
+	err := s.db.QueryRowContext(ctx, "SELECT data FROM states WHERE user_key = ? ORDER BY timestamp DESC, id DESC LIMIT 1", userKey).Scan(&data)
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(data, &state)
+
+	return state, err
+}
+
+// RecentWords returns words asked in last limit states of user, the latest first.
+func (s *Service) RecentWords(ctx context.Context, userKey string, limit int) ([]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT data FROM states WHERE user_key = ? ORDER BY timestamp DESC, id DESC LIMIT ?", userKey, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []golearn.State
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		state := golearn.State{}
+		err = json.Unmarshal(data, &state)
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	return golearn.StateWords(states), rows.Err()
+}
+
+// ResetState resets user state
+func (s *Service) ResetState(ctx context.Context, userKey string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if userKey == "" {
+		return errors.New("user key is empty")
+	}
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM states WHERE user_key = ?", userKey)
+
+	return err
+}
+
+// InsertWord inserts new row to words table
+func (s *Service) InsertWord(ctx context.Context, w golearn.Row) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	spellings, err := variants(w.Spellings)
+	if err != nil {
+		return err
+	}
+
+	translations, err := variants(w.Translations)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO words (word, translate, category, spellings, translations, owner) VALUES (?, ?, ?, ?, ?, ?)",
+		w.Word, w.Translate, w.Category, spellings, translations, w.Owner)
+
+	return err
+}
+
+// InsertUser inserts new user to users table
+func (s *Service) InsertUser(ctx context.Context, user golearn.User) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, "INSERT INTO users (user_id, username, name, mode, category, direction, latin_input, answers) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		user.UserID, user.Username, user.Name, user.Mode, user.Category, user.Direction, user.LatinInput, user.Answers)
+
+	return err
+}
+
+// UpdateUser updates user
+func (s *Service) UpdateUser(ctx context.Context, user golearn.User) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, "UPDATE users SET username = ?, name = ?, mode = ?, category = ?, direction = ?, latin_input = ?, answers = ? WHERE user_id = ?",
+		user.Username, user.Name, user.Mode, user.Category, user.Direction, user.LatinInput, user.Answers, user.UserID)
+
+	return err
+}
+
+// ExistUser returns bool if user already exists in db
+func (s *Service) ExistUser(ctx context.Context, user golearn.User) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if user.UserID == "" {
+		return false, errors.New("passed user has empty id")
+	}
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE user_id = ?)", user.UserID).Scan(&exists)
+
+	return exists, err
+}
+
+// GetUser returns user from db
+func (s *Service) GetUser(ctx context.Context, userID string) (golearn.User, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	u := golearn.User{}
+	if userID == "" {
+		return u, errors.New("passed user id is empty")
+	}
+
+	err := s.db.QueryRowContext(ctx, "SELECT user_id, username, name, mode, category, direction, latin_input, answers FROM users WHERE user_id = ?", userID).
+		Scan(&u.UserID, &u.Username, &u.Name, &u.Mode, &u.Category, &u.Direction, &u.LatinInput, &u.Answers)
+
+	return u, err
+}
+
+// SetUserMode sets new mode for passed user id
+func (s *Service) SetUserMode(ctx context.Context, userID string, mode string) error {
+	return s.setUserField(ctx, userID, "mode", mode)
+}
+
+// SetUserDirection sets new direction of questions for passed user id
+func (s *Service) SetUserDirection(ctx context.Context, userID string, direction string) error {
+	return s.setUserField(ctx, userID, "direction", direction)
+}
+
+// SetUserLatinInput enables or disables conversion of answers typed on Latin keyboard for passed user id
+func (s *Service) SetUserLatinInput(ctx context.Context, userID string, enabled bool) error {
+	return s.setUserField(ctx, userID, "latin_input", enabled)
+}
+
+// SetUserAnswers sets count of answers in picking mode for passed user id
+func (s *Service) SetUserAnswers(ctx context.Context, userID string, count int) error {
+	return s.setUserField(ctx, userID, "answers", count)
+}
+
+// SetUserCategory sets category of questions for passed user id
+func (s *Service) SetUserCategory(ctx context.Context, userID string, category string) error {
+	return s.setUserField(ctx, userID, "category", category)
+}
+
+// setUserField sets value of user column, column name isn't user input.
+func (s *Service) setUserField(ctx context.Context, userID string, column string, value interface{}) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, "UPDATE users SET "+column+" = ? WHERE user_id = ?", value, userID)
+
+	return err
+}
+
+// GetCategories returns list of unique categories based on words table.
+// Global categories are returned along with own collections of passed user.
+func (s *Service) GetCategories(ctx context.Context, userID string) ([]golearn.Category, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var categories []golearn.Category
+
+	rows, err := s.db.QueryContext(ctx, "SELECT category, COUNT(*) FROM words WHERE category <> '' AND owner IN ('', ?) GROUP BY category ORDER BY category", userID)
+	if err != nil {
+		return categories, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := golearn.Category{}
+		err = rows.Scan(&c.Name, &c.Words)
+		if err != nil {
+			return categories, err
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+// DeleteWordsByCategory removes words of category owned by passed user.
+// Empty user id removes global words only.
+func (s *Service) DeleteWordsByCategory(ctx context.Context, userID string, category string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM words WHERE category = ? AND owner = ?", category, userID)
+
+	return err
+}
+
+// InsertActivity saves user answer.
+func (s *Service) InsertActivity(ctx context.Context, activity golearn.Activity) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	state, err := json.Marshal(activity.State)
+	if err != nil {
+		return err
+	}
+
+	columns := "user_id, answer, is_right, verdict, similarity, state, timestamp"
+	args := []interface{}{activity.UserID, activity.Answer, activity.IsRight, activity.Verdict, activity.Similarity, state,
+		s.dialect.Time(activity.Timestamp)}
+
+	if s.dialect.DateParts {
+		timestamp := activity.Timestamp.UTC()
+		year, month, day := timestamp.Date()
+		_, week := timestamp.ISOWeek()
+
+		columns += ", year, month, week, day"
+		args = append(args, year, int(month), week, day)
+	}
+
+	_, err = s.db.ExecContext(ctx, "INSERT INTO activities ("+columns+") VALUES (?"+strings.Repeat(", ?", len(args)-1)+")", args...)
+
+	return err
+}
+
+// GetStatistics returns count of right and wrong answers of user for passed day, ISO week and month.
+// Week is counted in ISO year of the day. Dates of answers are compared in UTC.
+func (s *Service) GetStatistics(ctx context.Context, userID string, year int, month int, week int, day int) (golearn.Statistics, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var statistics golearn.Statistics
+	var err error
+
+	statistics.Today, err = s.statRow(ctx, userID, s.dialect.Day, year, month, day)
+	if err != nil {
+		return statistics, err
+	}
+
+	statistics.Week, err = s.statRow(ctx, userID, s.dialect.Week, golearn.WeekYear(year, month, day), week)
+	if err != nil {
+		return statistics, err
+	}
+
+	statistics.Month, err = s.statRow(ctx, userID, s.dialect.Month, year, month)
+
+	return statistics, err
+}
+
+func (s *Service) statRow(ctx context.Context, userID string, period string, args ...interface{}) (golearn.StatRow, error) {
+	row := golearn.StatRow{}
+
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(is_right), 0) FROM activities WHERE user_id = ? AND "+period,
+		append([]interface{}{userID}, args...)...).Scan(&row.Total, &row.Right)
+	row.Wrong = row.Total - row.Right
+
+	return row, err
+}
+
+// reviewColumns are columns of reviews table scanned by queryReview.
+const reviewColumns = "user_id, word, category, ease_factor, interval_days, repetitions, box, due"
+
+// GetReview returns user review of passed row.
+// New review is returned if user has never reviewed the row.
+func (s *Service) GetReview(ctx context.Context, userID string, row golearn.Row) (golearn.Review, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	review, err := s.queryReview(ctx, "SELECT "+reviewColumns+" FROM reviews WHERE user_id = ? AND word = ? AND category = ?",
+		userID, row.Word, row.Category)
+	if err == sql.ErrNoRows {
+		return golearn.NewReview(userID, row), nil
+	}
+
+	return review, err
+}
+
+// SetReview inserts or updates user review of the word.
+func (s *Service) SetReview(ctx context.Context, review golearn.Review) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, "INSERT INTO reviews ("+reviewColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?) "+
+		s.dialect.Upsert([]string{"user_id", "word", "category"}, []string{"ease_factor", "interval_days", "repetitions", "box", "due"}),
+		review.UserID, review.Word, review.Category, review.EaseFactor, review.Interval, review.Repetitions, review.Box, s.dialect.Time(review.Due))
+
+	return err
+}
+
+// GetBoxes returns Leitner boxes of user with count of words in each box.
+// Empty boxes are not returned.
+func (s *Service) GetBoxes(ctx context.Context, userID string) ([]golearn.Box, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var boxes []golearn.Box
+
+	rows, err := s.db.QueryContext(ctx, "SELECT box, COUNT(*) FROM reviews WHERE user_id = ? AND box > 0 GROUP BY box ORDER BY box", userID)
+	if err != nil {
+		return boxes, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		b := golearn.Box{}
+		err = rows.Scan(&b.Number, &b.Words)
+		if err != nil {
+			return boxes, err
+		}
+		boxes = append(boxes, b)
+	}
+
+	return boxes, rows.Err()
+}
+
+// scanner is implemented by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (s *Service) queryRow(ctx context.Context, query string, args ...interface{}) (golearn.Row, error) {
+	return scanRow(s.db.QueryRowContext(ctx, query, args...))
+}
+
+func (s *Service) queryRows(ctx context.Context, query string, args ...interface{}) ([]golearn.Row, error) {
+	var r []golearn.Row
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanRow(rows)
+		if err != nil {
+			return r, err
+		}
+		r = append(r, row)
+	}
+
+	return r, rows.Err()
+}
+
+// scanRow returns word scanned from result of query which selects wordColumns.
+func scanRow(sc scanner) (golearn.Row, error) {
+	r := golearn.Row{}
+
+	var spellings, translations sql.NullString
+	err := sc.Scan(&r.ID, &r.Word, &r.Translate, &r.Category, &spellings, &translations, &r.Owner)
+	if err != nil {
+		return r, err
+	}
+
+	if spellings.Valid {
+		err = json.NewDecoder(strings.NewReader(spellings.String)).Decode(&r.Spellings)
+		if err != nil {
+			return r, err
+		}
+	}
+
+	if translations.Valid {
+		err = json.NewDecoder(strings.NewReader(translations.String)).Decode(&r.Translations)
+	}
+
+	return r, err
+}
+
+func (s *Service) queryReview(ctx context.Context, query string, args ...interface{}) (golearn.Review, error) {
+	review := golearn.Review{}
+
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&review.UserID, &review.Word, &review.Category, &review.EaseFactor,
+		&review.Interval, &review.Repetitions, &review.Box, timeValue{&review.Due})
+
+	return review, err
+}
+
+// timeValue scans time saved as time.Time or unix nanoseconds to t.
+type timeValue struct {
+	t *time.Time
+}
+
+// Scan implements sql.Scanner.
+func (v timeValue) Scan(src interface{}) error {
+	switch t := src.(type) {
+	case time.Time:
+		*v.t = t.UTC()
+	case int64:
+		*v.t = time.Unix(0, t).UTC()
+	default:
+		return fmt.Errorf("unsupported type %T of time", src)
+	}
+
+	return nil
+}
+
+// notIn returns condition which excludes passed values of the column and its arguments,
+// empty condition is returned for no values.
+func notIn(column string, values []string) (string, []interface{}) {
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+
+	return " AND " + column + " NOT IN (?" + strings.Repeat(", ?", len(values)-1) + ")", args
+}
+
+// variants returns variants of the word encoded to JSON array, nil is returned for no variants.
+func variants(v []string) (interface{}, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
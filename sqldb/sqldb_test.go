@@ -0,0 +1,43 @@
+package sqldb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeValueScan(t *testing.T) {
+	due := time.Date(2019, 02, 21, 10, 30, 0, 500, time.UTC)
+
+	testCases := map[string]struct {
+		Src      interface{}
+		Expected time.Time
+		Error    error
+	}{
+		"time": {
+			Src:      due.In(time.FixedZone("KST", 9*60*60)),
+			Expected: due,
+		},
+		"unix nanoseconds": {
+			Src:      due.UnixNano(),
+			Expected: due,
+		},
+		"unsupported type": {
+			Src:   "2019-02-21",
+			Error: errors.New("unsupported type string of time"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var scanned time.Time
+
+			err := timeValue{&scanned}.Scan(tc.Src)
+
+			assert.Equal(t, tc.Error, err)
+			assert.Equal(t, tc.Expected, scanned)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/sergeiten/golearn/sqldb"
+)
+
+// schema creates tables used by Service. Statements can be run many times,
+// existing tables are kept as is. Changes of existing tables are made by migrations.
+// Times are saved as unix nanoseconds, date parts of activities are saved
+// separately because SQLite can't get ISO week of the date.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS words (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		word TEXT NOT NULL,
+		translate TEXT NOT NULL,
+		category TEXT NOT NULL DEFAULT '',
+		spellings TEXT NULL,
+		translations TEXT NULL,
+		owner TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS words_owner_category ON words (owner, category)`,
+	`CREATE INDEX IF NOT EXISTS words_word ON words (word)`,
+	`CREATE TABLE IF NOT EXISTS users (
+		user_id TEXT NOT NULL PRIMARY KEY,
+		username TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL DEFAULT '',
+		mode TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL DEFAULT '',
+		direction TEXT NOT NULL DEFAULT '',
+		latin_input INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS states (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_key TEXT NOT NULL,
+		timestamp INTEGER NOT NULL,
+		data TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS states_user_key_timestamp ON states (user_key, timestamp)`,
+	`CREATE TABLE IF NOT EXISTS activities (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT NOT NULL,
+		answer TEXT NOT NULL DEFAULT '',
+		is_right INTEGER NOT NULL,
+		verdict TEXT NOT NULL DEFAULT '',
+		similarity REAL NOT NULL DEFAULT 0,
+		state TEXT NOT NULL,
+		timestamp INTEGER NOT NULL,
+		year INTEGER NOT NULL,
+		month INTEGER NOT NULL,
+		week INTEGER NOT NULL,
+		day INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS activities_user_id_year ON activities (user_id, year)`,
+	`CREATE TABLE IF NOT EXISTS reviews (
+		user_id TEXT NOT NULL,
+		word TEXT NOT NULL,
+		category TEXT NOT NULL DEFAULT '',
+		ease_factor REAL NOT NULL,
+		interval_days INTEGER NOT NULL DEFAULT 0,
+		repetitions INTEGER NOT NULL DEFAULT 0,
+		box INTEGER NOT NULL DEFAULT 0,
+		due INTEGER NOT NULL,
+		PRIMARY KEY (user_id, word, category)
+	)`,
+	`CREATE INDEX IF NOT EXISTS reviews_user_id_due ON reviews (user_id, due)`,
+	`CREATE TABLE IF NOT EXISTS migrations (
+		version INTEGER NOT NULL PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		applied_at INTEGER NOT NULL
+	)`,
+}
+
+// migrations are applied in order, version of every next migration is greater by one.
+// Applied migrations must never be changed, add new one instead.
+var migrations = []sqldb.Migration{
+	{
+		Version:     1,
+		Description: "add answers count of users",
+		Up: func(db *sql.DB) error {
+			return sqldb.AddColumn(db, "users", "answers", "INTEGER NOT NULL DEFAULT 0")
+		},
+	},
+}
+
+// CreateSchema creates tables of the bot in passed database if they don't exist
+// and applies migrations which haven't been applied yet.
+func CreateSchema(db *sql.DB) error {
+	return sqldb.CreateSchema(db, Dialect, schema, migrations)
+}
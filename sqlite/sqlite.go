@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/sqldb"
+	// registers sqlite driver for database/sql
+	_ "modernc.org/sqlite"
+)
+
+// weekYear is ISO year of activity, first days of January may belong to the last week
+// of previous year and last days of December to the first week of next year.
+const weekYear = "CASE WHEN month = 1 AND week >= 52 THEN year - 1 WHEN month = 12 AND week = 1 THEN year + 1 ELSE year END"
+
+// Dialect of sqlite database. Times are saved as unix nanoseconds, date parts of activities
+// are saved separately because SQLite can't get ISO week of the date.
+var Dialect = sqldb.Dialect{
+	Name:         "sqlite",
+	Random:       "RANDOM()",
+	InsertIgnore: "INSERT OR IGNORE",
+	Upsert: func(key []string, columns []string) string {
+		set := make([]string, len(columns))
+		for i, c := range columns {
+			set[i] = c + " = excluded." + c
+		}
+
+		return "ON CONFLICT (" + strings.Join(key, ", ") + ") DO UPDATE SET " + strings.Join(set, ", ")
+	},
+	Time: func(t time.Time) interface{} {
+		return t.UnixNano()
+	},
+	DateParts: true,
+	Day:       "year = ? AND month = ? AND day = ?",
+	Week:      weekYear + " = ? AND week = ?",
+	Month:     "year = ? AND month = ?",
+}
+
+// New returns new instance of service. Database name from config is path of database file,
+// the file and schema are created if they don't exist yet.
+func New(cfg *golearn.Config) (*sqldb.Service, error) {
+	db, err := Open(cfg.Database.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = CreateSchema(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return sqldb.New(db, Dialect, cfg.Database.CallTimeout()), nil
+}
+
+// NewWithDB returns instance of service which uses passed database.
+// Schema has to be created by caller, calls are limited by context of caller only.
+func NewWithDB(db *sql.DB) *sqldb.Service {
+	return sqldb.New(db, Dialect, 0)
+}
+
+// Open returns opened sqlite database of passed file.
+// Only one connection is used, so writes from concurrent requests don't fail with locked database.
+func Open(filename string) (*sql.DB, error) {
+	if filename == "" {
+		return nil, errors.New("database file name is empty")
+	}
+
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	err = db.Ping()
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/dbtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golearn")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var n int
+	dbtest.Run(t, func(t *testing.T) golearn.DBService {
+		n++
+
+		cfg := &golearn.Config{}
+		cfg.Database.Name = filepath.Join(dir, "test"+strconv.Itoa(n)+".db")
+
+		service, err := New(cfg)
+		if err != nil {
+			t.Fatalf("failed to create test db: %v", err)
+		}
+
+		return service
+	})
+}
+
+func TestNewCreatesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golearn")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := &golearn.Config{}
+	cfg.Database.Name = filepath.Join(dir, "golearn.db")
+
+	service, err := New(cfg)
+	assert.Nil(t, err)
+
+	assert.Nil(t, service.InsertWord(context.Background(), golearn.Row{Word: "가다", Translate: "to go", Category: "verbs"}))
+	service.Close()
+
+	// words are kept after the file is opened again
+	service, err = New(cfg)
+	assert.Nil(t, err)
+	defer service.Close()
+
+	categories, err := service.GetCategories(context.Background(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, []golearn.Category{{Name: "verbs", Words: 1}}, categories)
+}
+
+func TestNewWithoutFile(t *testing.T) {
+	_, err := New(&golearn.Config{})
+
+	assert.EqualError(t, err, "database file name is empty")
+}
+
+func TestCreateSchemaMigratesOldDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golearn")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "golearn.db")
+
+	db, err := Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+
+	// users table as it was before answers count was added
+	_, err = db.Exec(`CREATE TABLE users (
+		user_id TEXT NOT NULL PRIMARY KEY,
+		username TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL DEFAULT '',
+		mode TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL DEFAULT '',
+		direction TEXT NOT NULL DEFAULT '',
+		latin_input INTEGER NOT NULL DEFAULT 0
+	)`)
+	assert.Nil(t, err)
+
+	_, err = db.Exec("INSERT INTO users (user_id, username, name, mode) VALUES ('177374215', 'sergeiten', 'Sergei', 'picking')")
+	assert.Nil(t, err)
+
+	assert.Nil(t, CreateSchema(db))
+	// migrations are applied only once
+	assert.Nil(t, CreateSchema(db))
+
+	var version int
+	assert.Nil(t, db.QueryRow("SELECT MAX(version) FROM migrations").Scan(&version))
+	assert.Equal(t, migrations[len(migrations)-1].Version, version)
+
+	service := NewWithDB(db)
+	defer service.Close()
+
+	ctx := context.Background()
+	assert.Nil(t, service.SetUserAnswers(ctx, "177374215", 6))
+
+	user, err := service.GetUser(ctx, "177374215")
+	assert.Nil(t, err)
+	assert.Equal(t, 6, user.Answers)
+	assert.Equal(t, "sergeiten", user.Username)
+}
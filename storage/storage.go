@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/memory"
+	"github.com/sergeiten/golearn/mongo"
+	"github.com/sergeiten/golearn/mysql"
+	"github.com/sergeiten/golearn/sqlite"
+)
+
+// Names of supported storage drivers.
+const (
+	DriverMongo  = "mongo"
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+	DriverMemory = "memory"
+)
+
+// New returns database service of driver set in config.
+// Mongo is used if driver isn't set. SQLite keeps database in file named as database in config.
+// Memory database is lost on exit, it's filled by word list of config on start.
+func New(cfg *golearn.Config) (golearn.DBService, error) {
+	switch cfg.Database.Driver {
+	case "", DriverMongo:
+		service, err := mongo.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return service, nil
+	case DriverMySQL:
+		service, err := mysql.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return service, nil
+	case DriverSQLite:
+		service, err := sqlite.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return service, nil
+	case DriverMemory:
+		words, err := readWords(cfg.Database.Words)
+		if err != nil {
+			return nil, err
+		}
+		return memory.New(memory.Config{Words: words}), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+	}
+}
+
+// readWords returns words of word list file, there are no words if path isn't set.
+func readWords(path string) ([]golearn.Row, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read word list")
+	}
+
+	words, err := golearn.GetWords(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse word list %s", path)
+	}
+
+	return words, nil
+}
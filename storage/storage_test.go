@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithUnknownDriver(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.Driver = "oracle"
+
+	service, err := New(cfg)
+
+	assert.Nil(t, service)
+	assert.EqualError(t, err, `unknown database driver "oracle"`)
+}
+
+func TestNewSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golearn")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := &golearn.Config{}
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Name = filepath.Join(dir, "golearn.db")
+
+	service, err := New(cfg)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, service)
+
+	service.Close()
+}
+
+func TestNewMemory(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.Driver = DriverMemory
+
+	service, err := New(cfg)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, service)
+}
+
+func TestNewMemoryWithWords(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.Driver = DriverMemory
+	cfg.Database.Words = "../words.json"
+
+	service, err := New(cfg)
+
+	if assert.Nil(t, err) {
+		categories, err := service.GetCategories(context.Background(), "")
+		assert.Nil(t, err)
+		assert.NotEmpty(t, categories)
+	}
+}
+
+func TestNewMemoryWithMissingWords(t *testing.T) {
+	cfg := &golearn.Config{}
+	cfg.Database.Driver = DriverMemory
+	cfg.Database.Words = "missing.json"
+
+	service, err := New(cfg)
+
+	assert.Nil(t, service)
+	assert.Error(t, err)
+}
@@ -1,25 +1,22 @@
 package telegram
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
-	"math"
 	"net/http"
-	"strings"
-	"time"
 
 	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/engine"
 )
 
 // Handler telegram HTTP handler
 type Handler struct {
-	db       golearn.DBService
-	http     golearn.HTTPService
-	lang     golearn.Language
-	langCode string
-	cols     int
-	token    string
-	user     golearn.User
+	http   golearn.HTTPService
+	engine *engine.Engine
+	token  string
+	secret string
 }
 
 // HandlerConfig handler config
@@ -30,55 +27,61 @@ type HandlerConfig struct {
 	DefaultLanguage string
 	Token           string
 	ColsCount       int
+	Matcher         golearn.Matcher
+	RecentWords     int
+	// Secret is secret token of webhook, requests without it are rejected if it's set
+	Secret string
 }
 
 // New returns new instance of telegram handler
 func New(cfg HandlerConfig) *Handler {
 	return &Handler{
-		db:       cfg.DBService,
-		http:     cfg.HTTPService,
-		lang:     cfg.Lang,
-		langCode: cfg.DefaultLanguage,
-		cols:     cfg.ColsCount,
-		token:    cfg.Token,
+		http: cfg.HTTPService,
+		engine: engine.New(engine.Config{
+			DBService:   cfg.DBService,
+			Lang:        cfg.Lang,
+			ColsCount:   cfg.ColsCount,
+			Matcher:     cfg.Matcher,
+			RecentWords: cfg.RecentWords,
+		}),
+		token:  cfg.Token,
+		secret: cfg.Secret,
 	}
 }
 
 // Serve starts http handler
 func (h *Handler) Serve() error {
-	http.Handle("/"+h.token+"/processMessage/", h)
+	http.Handle(WebhookPath(h.token), h)
 
 	return nil
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	update, err := h.http.Parse(r)
-	golearn.LogPrint(err, "failed to parse update")
-
-	h.user, err = h.getOrCreateUser(update)
-	if err != nil {
-		golearn.LogPrint(err, "failed to get/create user")
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 		return
 	}
 
-	message, keyboard, err := h.handle(update)
-
-	if err != nil {
-		golearn.LogPrintf(err, "failed to handle %s command", update.Message)
-		_, err = fmt.Fprint(w, err.Error())
-		golearn.LogPrint(err, "failed to send response")
+	if h.secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretHeader)), []byte(h.secret)) != 1 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 		return
 	}
 
-	d, err := json.Marshal(keyboard)
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+	update, err := h.http.Parse(r)
 	if err != nil {
-		golearn.LogPrint(err, "failed to marshal reply keyboard")
+		golearn.LogPrint(err, "failed to parse update")
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	err = h.http.Send(update, message, string(d))
+	err = h.process(r.Context(), update)
 	if err != nil {
-		golearn.LogPrint(err, "failed to send message")
+		_, err = fmt.Fprint(w, err.Error())
+		golearn.LogPrint(err, "failed to send response")
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -87,174 +90,32 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	golearn.LogPrint(err, "failed to send response")
 }
 
-func (h *Handler) handle(update *golearn.Update) (string, ReplyMarkup, error) {
-	switch {
-	case update.Message == h.lang["main_menu"]:
-		return h.mainMenu(update)
-	case update.Message == "/start":
-		return h.mainMenu(update)
-	case update.Message == h.lang["help"]:
-		return h.help(update)
-	case update.Message == h.lang["start"]:
-		return h.start(update)
-	case update.Message == h.lang["next_word"]:
-		return h.start(update)
-	case update.Message == h.lang["again"]:
-		return h.again(update)
-	case update.Message == h.lang["settings"]:
-		return h.settings(update)
-	case update.Message == h.lang["statistics"]:
-		return h.statistics(update)
-	case update.Message == h.lang["mode_picking"]:
-		return h.setMode(golearn.ModePicking)
-	case update.Message == h.lang["mode_typing"]:
-		return h.setMode(golearn.ModeTyping)
-	case update.Message == h.lang["show_answer"]:
-		return h.showAnswer(update)
-	case update.Message == h.lang["categories"]:
-		return h.categories(update)
-	case strings.HasPrefix(update.Message, h.lang["categories_icon"]):
-		return h.setCategory(update)
-	case update.Message == h.lang["reset_category"]:
-		return h.resetCategory(update)
-	default:
-		return h.answer(update, time.Now)
-	}
-}
-
-func (h *Handler) getOrCreateUser(update *golearn.Update) (golearn.User, error) {
-	u := golearn.User{
-		UserID:   update.UserID,
-		Username: update.Username,
-		Name:     update.Name,
-		Mode:     golearn.ModePicking,
-	}
-
-	exist, err := h.db.ExistUser(u)
-	if err != nil {
-		return u, err
-	}
-
-	if exist {
-		return h.db.GetUser(u.UserID)
-	}
-
-	return u, h.db.InsertUser(u)
-}
+// process handles passed update and sends reply to the user.
+// It's shared by webhook and polling, errors are logged and returned.
+func (h *Handler) process(ctx context.Context, update *golearn.Update) error {
+	reply, err := h.engine.Handle(ctx, update)
 
-func (h *Handler) settings(update *golearn.Update) (message string, markup ReplyMarkup, err error) {
-	keyboard := ReplyMarkup{
-		Keyboard: [][]string{
-			{
-				h.lang["mode_picking"],
-				h.lang["mode_typing"],
-				h.lang["categories"],
-			},
-		},
-		ResizeKeyboard: true,
+	if update.CallbackID != "" {
+		// acknowledge pressed inline button whether it's handled or not
+		ackErr := h.http.AnswerCallback(ctx, update)
+		golearn.LogPrint(ackErr, "failed to answer callback query")
 	}
 
-	return h.lang["mode_explain"], keyboard, nil
-}
-
-func (h *Handler) statistics(update *golearn.Update) (message string, markup ReplyMarkup, err error) {
-	year, month, day := time.Now().Date()
-	_, week := time.Now().ISOWeek()
-
-	statistics, err := h.db.GetStatistics(update.UserID, year, int(month), week, day)
 	if err != nil {
-		return "", ReplyMarkup{}, err
-	}
-
-	message = h.lang["statistics_text"] + "\n\n"
-
-	message += h.lang["statistics_period_today"] + "\n"
-	message += fmt.Sprintf(h.lang["statistics_period_summary"], statistics.Today.Total, statistics.Today.Right, statistics.Today.Wrong) + "\n"
-
-	message += h.lang["statistics_period_week"] + "\n"
-	message += fmt.Sprintf(h.lang["statistics_period_summary"], statistics.Week.Total, statistics.Week.Right, statistics.Week.Wrong) + "\n"
-
-	message += h.lang["statistics_period_month"] + "\n"
-	message += fmt.Sprintf(h.lang["statistics_period_summary"], statistics.Month.Total, statistics.Month.Right, statistics.Month.Wrong)
-
-	return message, h.mainMenuKeyboard(), nil
-}
-
-func (h *Handler) categories(update *golearn.Update) (message string, markup ReplyMarkup, err error) {
-	categories, err := h.db.GetCategories(update.UserID)
-	if err != nil {
-		return "", ReplyMarkup{}, err
-	}
-
-	var reply ReplyMarkup
-	var options []string
-
-	r := float64(len(categories)) / float64(h.cols)
-	rows := int(math.Ceil(r))
-
-	keyboard := make([][]string, rows+1)
-
-	for _, a := range categories {
-		options = append(options, h.lang["categories_icon"]+" "+a.Name)
-	}
-
-	start := 0
-	for i := 0; i < rows; i++ {
-		if i > 0 {
-			start = h.cols * i
-		}
-
-		finish := start + h.cols
-
-		if finish > len(categories) {
-			finish = len(options)
-		}
-
-		keyboard[i] = options[start:finish]
-	}
-
-	keyboard[rows] = []string{
-		h.lang["reset_category"],
-		h.lang["main_menu"],
-	}
-
-	reply.Keyboard = keyboard
-	reply.ResizeKeyboard = true
-
-	return h.lang["pick_category"], reply, nil
-}
-
-func (h *Handler) setCategory(update *golearn.Update) (message string, markup ReplyMarkup, err error) {
-	// remove category icon
-	category := strings.Trim(strings.Replace(update.Message, h.lang["categories_icon"], "", -1), " ")
-	err = h.db.SetUserCategory(h.user.UserID, category)
-	if err != nil {
-		return "", ReplyMarkup{}, err
+		golearn.LogPrintf(err, "failed to handle %s command", update.Message)
+		return err
 	}
 
-	keyboard := h.mainMenuKeyboard()
-
-	return h.lang["category_set"], keyboard, nil
-}
-
-func (h *Handler) resetCategory(update *golearn.Update) (message string, markup ReplyMarkup, err error) {
-	err = h.db.SetUserCategory(h.user.UserID, "")
+	d, err := json.Marshal(replyMarkup(reply.Keyboard))
 	if err != nil {
-		return "", ReplyMarkup{}, err
+		golearn.LogPrint(err, "failed to marshal reply keyboard")
+		return err
 	}
 
-	keyboard := h.mainMenuKeyboard()
-
-	return h.lang["category_reset"], keyboard, nil
-}
-
-func (h *Handler) setMode(mode string) (message string, markup ReplyMarkup, err error) {
-	err = h.db.SetUserMode(h.user.UserID, mode)
+	err = h.http.Send(ctx, update, reply.Text, string(d))
 	if err != nil {
-		return "", ReplyMarkup{}, err
+		golearn.LogPrint(err, "failed to send message")
 	}
 
-	keyboard := h.mainMenuKeyboard()
-
-	return h.lang["mode_set"], keyboard, nil
+	return nil
 }
@@ -1,39 +1,56 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sergeiten/golearn"
 )
 
+// DefaultTimeout limits call of Bot API if HTTPConfig doesn't set timeout.
+const DefaultTimeout = 10 * time.Second
+
 // HTTP implements HTTPService interface for telegram.
 type HTTP struct {
-	api   string
-	token string
+	api     string
+	token   string
+	client  *http.Client
+	timeout time.Duration
 }
 
 // HTTPConfig config for making HTTP instance.
 type HTTPConfig struct {
 	API   string
 	Token string
+	// Timeout limits every call of Bot API, long polling call is limited
+	// by its own timeout plus this one
+	Timeout time.Duration
 }
 
 // NewHTTP returns HTTP instance.
 func NewHTTP(config HTTPConfig) *HTTP {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
 	return &HTTP{
-		api:   config.API,
-		token: config.Token,
+		api:     config.API,
+		token:   config.Token,
+		client:  &http.Client{},
+		timeout: timeout,
 	}
 }
 
 // Send sends passed message and keyboard struct to the client.
-func (h *HTTP) Send(update *golearn.Update, message string, keyboard string) error {
-	client := &http.Client{}
+func (h *HTTP) Send(ctx context.Context, update *golearn.Update, message string, keyboard string) error {
 	values := url.Values{}
 
 	values.Set("text", message)
@@ -41,26 +58,30 @@ func (h *HTTP) Send(update *golearn.Update, message string, keyboard string) err
 	values.Set("parse_mode", "HTML")
 	values.Set("reply_markup", keyboard)
 
-	req, err := http.NewRequest("POST", h.api+"/bot"+h.token+"/sendMessage", strings.NewReader(values.Encode()))
-
-	if err != nil {
-		return err
-	}
+	return h.post(ctx, "sendMessage", values)
+}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+// AnswerCallback acknowledges callback query of passed update, so client stops
+// showing progress on pressed inline button.
+func (h *HTTP) AnswerCallback(ctx context.Context, update *golearn.Update) error {
+	values := url.Values{}
 
-	response, err := client.Do(req)
+	values.Set("callback_query_id", update.CallbackID)
 
-	if err != nil {
-		return err
-	}
+	return h.post(ctx, "answerCallbackQuery", values)
+}
 
-	defer response.Body.Close()
+// post sends passed values to Bot API method without decoding its result.
+// Error is returned if the message is rejected, e.g. because of malformed HTML.
+func (h *HTTP) post(ctx context.Context, method string, values url.Values) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
 
-	return nil
+	return h.call(ctx, method, values, nil)
 }
 
 // Parse parses passed http request and returns general golearn.Update object.
+// Callback query of inline button is returned as update with button data as message.
 func (h *HTTP) Parse(r *http.Request) (*golearn.Update, error) {
 	body, err := ioutil.ReadAll(r.Body)
 
@@ -76,11 +97,72 @@ func (h *HTTP) Parse(r *http.Request) (*golearn.Update, error) {
 		return nil, err
 	}
 
-	return &golearn.Update{
-		ChatID:   strconv.Itoa(tUpdate.Message.Chat.ID),
-		UserID:   strconv.Itoa(tUpdate.Message.Chat.ID),
-		Username: tUpdate.Message.Chat.Username,
-		Name:     tUpdate.Message.Chat.Firstname,
-		Message:  tUpdate.Message.Text,
-	}, nil
+	return tUpdate.toUpdate(), nil
+}
+
+// Updates returns updates received by bot starting from passed offset, which is
+// the id of the first update to return. Request is held by Bot API for timeout
+// seconds until any update comes, it's cancelled when ctx is done.
+func (h *HTTP) Updates(ctx context.Context, offset int, timeout int) ([]TUpdate, error) {
+	values := url.Values{}
+
+	values.Set("offset", strconv.Itoa(offset))
+	values.Set("timeout", strconv.Itoa(timeout))
+	values.Set("allowed_updates", `["message","callback_query"]`)
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout+time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	var updates []TUpdate
+	err := h.call(ctx, "getUpdates", values, &updates)
+
+	return updates, err
+}
+
+// call calls passed Bot API method and decodes its result to passed value.
+// Error is returned if Bot API responds with status other than 2xx or with ok false.
+func (h *HTTP) call(ctx context.Context, method string, values url.Values, result interface{}) error {
+	response, err := h.do(ctx, method, values)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	body := struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}{}
+
+	err = json.NewDecoder(response.Body).Decode(&body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("failed to call %s: %s %s", method, response.Status, body.Description)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !body.OK {
+		return fmt.Errorf("failed to call %s: %s", method, body.Description)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(body.Result, result)
+}
+
+// do posts passed values to Bot API method, the call is cancelled when ctx is done.
+func (h *HTTP) do(ctx context.Context, method string, values url.Values) (*http.Response, error) {
+	req, err := http.NewRequest("POST", h.api+"/bot"+h.token+"/"+method, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return h.client.Do(req)
 }
@@ -1,10 +1,16 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sergeiten/golearn"
 	"github.com/stretchr/testify/assert"
@@ -44,3 +50,210 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, expectedUpdate, u)
 	assert.Equal(t, nil, err)
 }
+
+func TestParseRecordedUpdates(t *testing.T) {
+	httpService := NewHTTP(HTTPConfig{})
+
+	testCases := map[string]struct {
+		File     string
+		Expected *golearn.Update
+	}{
+		"message": {
+			File: "testdata/message.json",
+			Expected: &golearn.Update{
+				ChatID:   "177374215",
+				UserID:   "177374215",
+				Username: "sergeiten",
+				Name:     "Sergei",
+				Message:  "⚡ Начать",
+			},
+		},
+		"callback query": {
+			File: "testdata/callback_query.json",
+			Expected: &golearn.Update{
+				ChatID:     "177374215",
+				UserID:     "177374215",
+				Username:   "sergeiten",
+				Name:       "Sergei",
+				Message:    "answer:5c6e5a1b:0",
+				CallbackID: "761370893423543219",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(tc.File)
+			if err != nil {
+				t.Fatalf("failed to open recorded update: %v", err)
+			}
+			defer f.Close()
+
+			req := httptest.NewRequest("POST", "/", f)
+
+			u, err := httpService.Parse(req)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Expected, u)
+		})
+	}
+}
+
+func TestHTTPAnswerCallback(t *testing.T) {
+	var path string
+	var values map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body, _ := ioutil.ReadAll(r.Body)
+		values = parseQuery(t, string(body))
+		_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer server.Close()
+
+	httpService := NewHTTP(HTTPConfig{
+		API:   server.URL,
+		Token: "token",
+	})
+
+	err := httpService.AnswerCallback(context.Background(), &golearn.Update{
+		ChatID:     "177374215",
+		CallbackID: "761370893423543219",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/bottoken/answerCallbackQuery", path)
+	assert.Equal(t, []string{"761370893423543219"}, values["callback_query_id"])
+}
+
+func TestHTTPSendRejected(t *testing.T) {
+	testCases := map[string]struct {
+		Status   int
+		Response string
+		Error    bool
+	}{
+		"sent": {
+			Status:   http.StatusOK,
+			Response: `{"ok":true,"result":{}}`,
+			Error:    false,
+		},
+		"bad request": {
+			Status:   http.StatusBadRequest,
+			Response: `{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`,
+			Error:    true,
+		},
+		"not ok": {
+			Status:   http.StatusOK,
+			Response: `{"ok":false,"description":"Bad Request: chat not found"}`,
+			Error:    true,
+		},
+		"server error without body": {
+			Status:   http.StatusBadGateway,
+			Response: "",
+			Error:    true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.Status)
+				_, _ = w.Write([]byte(tc.Response))
+			}))
+			defer server.Close()
+
+			httpService := NewHTTP(HTTPConfig{
+				API:   server.URL,
+				Token: "token",
+			})
+
+			err := httpService.Send(context.Background(), &golearn.Update{ChatID: "177374215"}, "<b>message", "{}")
+			assert.Equal(t, tc.Error, err != nil)
+		})
+	}
+}
+
+func TestHTTPSendTimeout(t *testing.T) {
+	// Bot API hangs until the test is over
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	httpService := NewHTTP(HTTPConfig{
+		API:     server.URL,
+		Token:   "token",
+		Timeout: 50 * time.Millisecond,
+	})
+
+	testCases := map[string]struct {
+		Context func() (context.Context, context.CancelFunc)
+	}{
+		"timeout of http": {
+			Context: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+		},
+		"cancelled context": {
+			Context: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := tc.Context()
+			defer cancel()
+
+			err := httpService.Send(ctx, &golearn.Update{ChatID: "177374215"}, "message", "{}")
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestMarshalReplyMarkup(t *testing.T) {
+	testCases := map[string]struct {
+		Markup   ReplyMarkup
+		Expected string
+	}{
+		"reply keyboard": {
+			Markup: ReplyMarkup{
+				Keyboard:       [][]string{{"start"}},
+				ResizeKeyboard: true,
+			},
+			Expected: `{"keyboard":[["start"]],"resize_keyboard":true}`,
+		},
+		"inline keyboard": {
+			Markup: ReplyMarkup{
+				InlineKeyboard: [][]InlineButton{{{Text: "идти", CallbackData: "answer:5c6e5a1b:0"}}},
+			},
+			Expected: `{"inline_keyboard":[[{"text":"идти","callback_data":"answer:5c6e5a1b:0"}]]}`,
+		},
+		"no keyboard": {
+			Markup:   ReplyMarkup{},
+			Expected: `{}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d, err := json.Marshal(tc.Markup)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Expected, string(d))
+		})
+	}
+}
+
+func parseQuery(t *testing.T, query string) map[string][]string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+	return values
+}
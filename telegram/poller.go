@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergeiten/golearn"
+)
+
+// DefaultPollTimeout is timeout of long polling request in seconds used when it's not configured.
+const DefaultPollTimeout = 30
+
+// retryDelay is delay before the next request after failed one.
+const retryDelay = 3 * time.Second
+
+// OffsetStore persists offset of the next update, so updates processed
+// before restart aren't received again.
+type OffsetStore interface {
+	Offset() (int, error)
+	SetOffset(offset int) error
+}
+
+// Poller receives updates by long polling getUpdates method of Bot API
+// and passes them to handler. It's used instead of webhook when bot has no
+// public HTTPS endpoint, e.g. when it runs locally.
+type Poller struct {
+	handler *Handler
+	http    *HTTP
+	offsets OffsetStore
+	timeout int
+}
+
+// PollerConfig config for making Poller instance.
+type PollerConfig struct {
+	Handler *Handler
+	HTTP    *HTTP
+	Offsets OffsetStore
+	Timeout int
+}
+
+// NewPoller returns Poller instance.
+func NewPoller(cfg PollerConfig) *Poller {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPollTimeout
+	}
+
+	return &Poller{
+		handler: cfg.Handler,
+		http:    cfg.HTTP,
+		offsets: cfg.Offsets,
+		timeout: timeout,
+	}
+}
+
+// Run removes webhook and polls updates one by one until ctx is done.
+// Offset is saved after every processed update.
+func (p *Poller) Run(ctx context.Context) error {
+	offset, err := p.offsets.Offset()
+	if err != nil {
+		return err
+	}
+
+	// Bot API doesn't return updates while webhook is set
+	err = p.http.DeleteWebhook(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		updates, err := p.http.Updates(ctx, offset, p.timeout)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			golearn.LogPrint(err, "failed to get updates")
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			// handling errors are logged by handler, update isn't retried
+			_ = p.handler.process(ctx, u.toUpdate())
+
+			offset = u.UpdateID + 1
+			err = p.offsets.SetOffset(offset)
+			golearn.LogPrint(err, "failed to save updates offset")
+		}
+	}
+}
+
+// FileOffset stores offset of the next update in file.
+type FileOffset struct {
+	path string
+}
+
+// NewFileOffset returns offset store which keeps offset in file by passed path.
+func NewFileOffset(path string) *FileOffset {
+	return &FileOffset{
+		path: path,
+	}
+}
+
+// Offset returns saved offset, zero is returned if nothing has been saved yet.
+func (f *FileOffset) Offset() (int, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(f.path))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
+
+// SetOffset saves passed offset. File is replaced at once,
+// so offset isn't lost if process is killed while writing.
+func (f *FileOffset) SetOffset(offset int) error {
+	tmp := f.path + ".tmp"
+
+	err := ioutil.WriteFile(tmp, []byte(strconv.Itoa(offset)), 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.path)
+}
@@ -0,0 +1,159 @@
+package telegram
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeBotAPI is local Bot API server which returns recorded updates
+// and keeps requests sent by bot.
+type fakeBotAPI struct {
+	mu       sync.Mutex
+	updates  []byte
+	offsets  []string
+	messages []string
+	acks     []string
+	cancel   context.CancelFunc
+
+	webhookDeleted bool
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/bot" + botToken + "/getUpdates":
+		f.offsets = append(f.offsets, r.PostForm.Get("offset"))
+
+		// the first request gets recorded updates, the next one stops poller
+		if len(f.offsets) == 1 {
+			_, _ = w.Write(f.updates)
+			return
+		}
+		f.cancel()
+		_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+	case "/bot" + botToken + "/deleteWebhook":
+		f.webhookDeleted = true
+		_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+	case "/bot" + botToken + "/sendMessage":
+		f.messages = append(f.messages, r.PostForm.Get("text"))
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	case "/bot" + botToken + "/answerCallbackQuery":
+		f.acks = append(f.acks, r.PostForm.Get("callback_query_id"))
+		_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestPollerRun(t *testing.T) {
+	updates, err := ioutil.ReadFile("testdata/get_updates.json")
+	if err != nil {
+		t.Fatalf("failed to read recorded updates: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "poller")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	offsets := NewFileOffset(filepath.Join(dir, "offset"))
+	assert.Nil(t, offsets.SetOffset(148790444))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := &fakeBotAPI{
+		updates: updates,
+		cancel:  cancel,
+	}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	telegramHTTP := NewHTTP(HTTPConfig{
+		API:   server.URL,
+		Token: botToken,
+	})
+
+	dbService := &mocks.DBService{}
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+	}
+
+	dbService.On("ExistUser", mock.Anything, user).Return(true, nil)
+	dbService.On("GetUser", mock.Anything, user.UserID).Return(user, nil)
+
+	poller := NewPoller(PollerConfig{
+		Handler: New(HandlerConfig{
+			DBService:       dbService,
+			HTTPService:     telegramHTTP,
+			Lang:            lang,
+			DefaultLanguage: "ru",
+			Token:           botToken,
+			ColsCount:       2,
+		}),
+		HTTP:    telegramHTTP,
+		Offsets: offsets,
+	})
+
+	err = poller.Run(ctx)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, api.webhookDeleted)
+	assert.Equal(t, []string{"148790444", "148790446"}, api.offsets)
+	assert.Equal(t, []string{lang["welcome"], lang["welcome"]}, api.messages)
+	assert.Equal(t, []string{"761370893423543220"}, api.acks)
+
+	offset, err := offsets.Offset()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 148790446, offset)
+
+	dbService.AssertExpectations(t)
+}
+
+func TestFileOffset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "poller")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	offsets := NewFileOffset(filepath.Join(dir, "offset"))
+
+	// nothing is saved yet
+	offset, err := offsets.Offset()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, offset)
+
+	assert.Nil(t, offsets.SetOffset(42))
+
+	offset, err = offsets.Offset()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 42, offset)
+}
@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// wordOf returns own word of user, so question asked to another user can be noticed.
+// Memory database numbers words in order of insertion, so ID of user's word follows its number.
+func wordOf(userID string) golearn.Row {
+	n, _ := strconv.Atoi(userID)
+
+	return golearn.Row{
+		ID:        n - 100000 + 1,
+		Word:      "word " + userID,
+		Translate: "translate " + userID,
+		Category:  "stress",
+		Owner:     userID,
+	}
+}
+
+// chatRecorder keeps messages sent to every chat.
+type chatRecorder struct {
+	golearn.HTTPService
+
+	mu       sync.Mutex
+	messages map[string][]string
+}
+
+func (c *chatRecorder) Send(ctx context.Context, update *golearn.Update, message string, keyboard string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages[update.ChatID] = append(c.messages[update.ChatID], message)
+	return nil
+}
+
+func (c *chatRecorder) Messages(chatID string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.messages[chatID]
+}
+
+// TestProcessConcurrently handles updates of many users at the same time, each user
+// switches to own mode and answers own question. Run it with -race flag.
+func TestProcessConcurrently(t *testing.T) {
+	const users = 300
+
+	modes := []string{golearn.ModePicking, golearn.ModeTyping, golearn.ModeLeitner}
+	modeCommands := map[string]string{
+		golearn.ModePicking: lang["mode_picking"],
+		golearn.ModeTyping:  lang["mode_typing"],
+		golearn.ModeLeitner: lang["mode_leitner"],
+	}
+
+	words := make([]golearn.Row, 0, users)
+	for i := 0; i < users; i++ {
+		words = append(words, wordOf(strconv.Itoa(100000+i)))
+	}
+
+	db := memory.New(memory.Config{Words: words})
+	recorder := &chatRecorder{
+		messages: map[string][]string{},
+	}
+
+	h := New(HandlerConfig{
+		DBService:       db,
+		HTTPService:     recorder,
+		Lang:            lang,
+		DefaultLanguage: "ru",
+		Token:           botToken,
+		ColsCount:       2,
+	})
+
+	send := func(userID string, message string) {
+		err := h.process(context.Background(), &golearn.Update{
+			ChatID:  userID,
+			UserID:  userID,
+			Name:    "user " + userID,
+			Message: message,
+		})
+		assert.Nil(t, err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			userID := strconv.Itoa(100000 + i)
+			mode := modes[i%len(modes)]
+
+			send(userID, "/start")
+			send(userID, modeCommands[mode])
+			send(userID, lang["start"])
+
+			state, _ := db.GetState(context.Background(), userID)
+			send(userID, state.AnswerOf(state.Question))
+		}(i)
+	}
+
+	wg.Wait()
+
+	now := time.Now().UTC()
+	_, week := now.ISOWeek()
+
+	for i := 0; i < users; i++ {
+		userID := strconv.Itoa(100000 + i)
+		mode := modes[i%len(modes)]
+		name := fmt.Sprintf("user %s in %s mode", userID, mode)
+
+		user, _ := db.GetUser(context.Background(), userID)
+		assert.Equal(t, mode, user.Mode, name)
+
+		state, _ := db.GetState(context.Background(), userID)
+		assert.Equal(t, wordOf(userID), state.Question, name)
+		assert.Equal(t, golearn.DefaultDirection(mode), state.Direction, name)
+
+		statistics, err := db.GetStatistics(context.Background(), userID, now.Year(), int(now.Month()), week, now.Day())
+		assert.Nil(t, err, name)
+		assert.Equal(t, golearn.StatRow{Total: 1, Right: 1}, statistics.Today, name)
+
+		assert.Equal(t, []string{
+			lang["welcome"],
+			lang["mode_set"],
+			state.Ask(),
+			lang["right"],
+		}, recorder.Messages(userID), name)
+	}
+}
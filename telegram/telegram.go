@@ -1,9 +1,40 @@
 package telegram
 
+import (
+	"strconv"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/engine"
+)
+
 // TUpdate ...
 type TUpdate struct {
-	UpdateID int      `json:"update_id"`
-	Message  TMessage `json:"message"`
+	UpdateID      int             `json:"update_id"`
+	Message       TMessage        `json:"message"`
+	CallbackQuery *TCallbackQuery `json:"callback_query,omitempty"`
+}
+
+// toUpdate returns general golearn.Update of telegram update.
+// Callback query of inline button is returned as update with button data as message.
+func (u TUpdate) toUpdate() *golearn.Update {
+	if q := u.CallbackQuery; q != nil {
+		return &golearn.Update{
+			ChatID:     strconv.Itoa(q.Message.Chat.ID),
+			UserID:     strconv.Itoa(q.From.ID),
+			Username:   q.From.Username,
+			Name:       q.From.Firstname,
+			Message:    q.Data,
+			CallbackID: q.ID,
+		}
+	}
+
+	return &golearn.Update{
+		ChatID:   strconv.Itoa(u.Message.Chat.ID),
+		UserID:   strconv.Itoa(u.Message.Chat.ID),
+		Username: u.Message.Chat.Username,
+		Name:     u.Message.Chat.Firstname,
+		Message:  u.Message.Text,
+	}
 }
 
 // TMessage ...
@@ -21,8 +52,71 @@ type TChat struct {
 	ID        int    `json:"id"`
 }
 
-// ReplyMarkup ...
+// TCallbackQuery represents press of inline keyboard button.
+// Message is the message with inline keyboard which button was pressed.
+type TCallbackQuery struct {
+	ID      string   `json:"id"`
+	From    TUser    `json:"from"`
+	Message TMessage `json:"message"`
+	Data    string   `json:"data"`
+}
+
+// TUser ...
+type TUser struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	Firstname string `json:"first_name"`
+}
+
+// ReplyMarkup represents either reply keyboard or inline keyboard attached to the message.
 type ReplyMarkup struct {
-	Keyboard       [][]string `json:"keyboard"`
-	ResizeKeyboard bool       `json:"resize_keyboard"`
+	Keyboard       [][]string       `json:"keyboard,omitempty"`
+	InlineKeyboard [][]InlineButton `json:"inline_keyboard,omitempty"`
+	ResizeKeyboard bool             `json:"resize_keyboard,omitempty"`
+}
+
+// InlineButton represents button of inline keyboard.
+// CallbackData is sent back in callback query when button is pressed.
+type InlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// replyMarkup returns Telegram keyboard of engine keyboard.
+// Buttons without data send their text when inline button is pressed.
+func replyMarkup(keyboard engine.Keyboard) ReplyMarkup {
+	var markup ReplyMarkup
+
+	if len(keyboard.Rows) == 0 {
+		return markup
+	}
+
+	if keyboard.Inline {
+		markup.InlineKeyboard = make([][]InlineButton, len(keyboard.Rows))
+		for i, row := range keyboard.Rows {
+			for _, b := range row {
+				data := b.Data
+				if data == "" {
+					data = b.Text
+				}
+
+				markup.InlineKeyboard[i] = append(markup.InlineKeyboard[i], InlineButton{
+					Text:         b.Text,
+					CallbackData: data,
+				})
+			}
+		}
+
+		return markup
+	}
+
+	markup.Keyboard = make([][]string, len(keyboard.Rows))
+	for i, row := range keyboard.Rows {
+		for _, b := range row {
+			markup.Keyboard[i] = append(markup.Keyboard[i], b.Text)
+		}
+	}
+	markup.ResizeKeyboard = true
+
+	return markup
 }
@@ -0,0 +1,51 @@
+package telegram
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sergeiten/golearn/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplyMarkup(t *testing.T) {
+	testCases := map[string]struct {
+		Keyboard engine.Keyboard
+		Expected string
+	}{
+		"no keyboard": {
+			Keyboard: engine.Keyboard{},
+			Expected: `{}`,
+		},
+		"reply keyboard": {
+			Keyboard: engine.Keyboard{
+				Rows: [][]engine.Button{
+					{{Text: lang["next_word"]}, {Text: lang["show_answer"]}},
+					{{Text: lang["main_menu"]}},
+				},
+			},
+			Expected: `{"keyboard":[["▶ Следующее слово","🤷 Показать ответ"],["/Главное Меню"]],"resize_keyboard":true}`,
+		},
+		"inline keyboard": {
+			Keyboard: engine.Keyboard{
+				Rows: [][]engine.Button{
+					{{Text: "test0", Data: "answer:5c6e5a1b:0"}, {Text: "test1", Data: "answer:5c6e5a1b:1"}},
+					{{Text: lang["main_menu"]}},
+				},
+				Inline: true,
+			},
+			Expected: `{"inline_keyboard":[[{"text":"test0","callback_data":"answer:5c6e5a1b:0"},{"text":"test1","callback_data":"answer:5c6e5a1b:1"}],[{"text":"/Главное Меню","callback_data":"/Главное Меню"}]]}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			byt, err := json.Marshal(replyMarkup(tc.Keyboard))
+			if err != nil {
+				t.Fatal("failed to marshal reply markup")
+			}
+
+			assert.Equal(t, tc.Expected, string(byt))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package telegram
+
+import (
+	"context"
+	"net/url"
+)
+
+// SecretHeader is header with secret token sent by Telegram in every webhook request.
+const SecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// maxBodySize is the biggest webhook request accepted by handler.
+const maxBodySize = 1 << 20
+
+// WebhookInfo represents current status of bot webhook.
+type WebhookInfo struct {
+	URL                  string `json:"url"`
+	HasCustomCertificate bool   `json:"has_custom_certificate"`
+	PendingUpdateCount   int    `json:"pending_update_count"`
+	LastErrorDate        int    `json:"last_error_date"`
+	LastErrorMessage     string `json:"last_error_message"`
+	MaxConnections       int    `json:"max_connections"`
+}
+
+// WebhookPath returns path of webhook handler for passed bot token.
+func WebhookPath(token string) string {
+	return "/" + token + "/processMessage/"
+}
+
+// SetWebhook registers passed public URL of handler as bot webhook. Telegram sends
+// secret in SecretHeader of every request, so handler can tell them from forged ones.
+func (h *HTTP) SetWebhook(ctx context.Context, webhookURL string, secret string) error {
+	values := url.Values{}
+
+	values.Set("url", webhookURL)
+	values.Set("allowed_updates", `["message","callback_query"]`)
+	if secret != "" {
+		values.Set("secret_token", secret)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	return h.call(ctx, "setWebhook", values, nil)
+}
+
+// DeleteWebhook removes bot webhook, so updates can be received by polling.
+func (h *HTTP) DeleteWebhook(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	return h.call(ctx, "deleteWebhook", url.Values{}, nil)
+}
+
+// WebhookInfo returns current status of bot webhook including last delivery error.
+func (h *HTTP) WebhookInfo(ctx context.Context) (WebhookInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	info := WebhookInfo{}
+	err := h.call(ctx, "getWebhookInfo", url.Values{}, &info)
+
+	return info, err
+}
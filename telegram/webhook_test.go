@@ -0,0 +1,196 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sergeiten/golearn"
+	"github.com/sergeiten/golearn/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetWebhook(t *testing.T) {
+	var path string
+	var values url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = r.ParseForm()
+		values = r.PostForm
+		_, _ = w.Write([]byte(`{"ok":true,"result":true,"description":"Webhook was set"}`))
+	}))
+	defer server.Close()
+
+	httpService := NewHTTP(HTTPConfig{
+		API:   server.URL,
+		Token: botToken,
+	})
+
+	err := httpService.SetWebhook(context.Background(), "https://golearn.example.com"+WebhookPath(botToken), "s3cr3t")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/bot"+botToken+"/setWebhook", path)
+	assert.Equal(t, "https://golearn.example.com/"+botToken+"/processMessage/", values.Get("url"))
+	assert.Equal(t, "s3cr3t", values.Get("secret_token"))
+}
+
+func TestWebhookInfo(t *testing.T) {
+	testCases := map[string]struct {
+		Response string
+		Info     WebhookInfo
+		Error    string
+	}{
+		"webhook with delivery error": {
+			Response: `{"ok":true,"result":{"url":"https://golearn.example.com/processMessage/","has_custom_certificate":false,"pending_update_count":3,"last_error_date":1459919266,"last_error_message":"Wrong response from the webhook: 401 Unauthorized","max_connections":40}}`,
+			Info: WebhookInfo{
+				URL:                "https://golearn.example.com/processMessage/",
+				PendingUpdateCount: 3,
+				LastErrorDate:      1459919266,
+				LastErrorMessage:   "Wrong response from the webhook: 401 Unauthorized",
+				MaxConnections:     40,
+			},
+		},
+		"invalid token": {
+			Response: `{"ok":false,"error_code":401,"description":"Unauthorized"}`,
+			Error:    "failed to call getWebhookInfo: Unauthorized",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.Response))
+			}))
+			defer server.Close()
+
+			httpService := NewHTTP(HTTPConfig{
+				API:   server.URL,
+				Token: botToken,
+			})
+
+			info, err := httpService.WebhookInfo(context.Background())
+
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Info, info)
+		})
+	}
+}
+
+func TestServeHTTPRejectsRequests(t *testing.T) {
+	testCases := map[string]struct {
+		Method string
+		Secret string
+		Body   string
+		Code   int
+	}{
+		"wrong method": {
+			Method: "GET",
+			Secret: "s3cr3t",
+			Body:   "",
+			Code:   http.StatusMethodNotAllowed,
+		},
+		"missing secret": {
+			Method: "POST",
+			Secret: "",
+			Body:   "{}",
+			Code:   http.StatusUnauthorized,
+		},
+		"wrong secret": {
+			Method: "POST",
+			Secret: "secret",
+			Body:   "{}",
+			Code:   http.StatusUnauthorized,
+		},
+		"too large body": {
+			Method: "POST",
+			Secret: "s3cr3t",
+			Body:   `{"message":{"text":"` + strings.Repeat("a", maxBodySize) + `"}}`,
+			Code:   http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbService := &mocks.DBService{}
+
+			handler = New(HandlerConfig{
+				DBService:       dbService,
+				HTTPService:     NewHTTP(HTTPConfig{}),
+				Lang:            lang,
+				DefaultLanguage: "ru",
+				Token:           botToken,
+				ColsCount:       2,
+				Secret:          "s3cr3t",
+			})
+
+			req := httptest.NewRequest(tc.Method, WebhookPath(botToken), strings.NewReader(tc.Body))
+			if tc.Secret != "" {
+				req.Header.Set(SecretHeader, tc.Secret)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.Code, w.Code)
+
+			// rejected requests never reach handler
+			dbService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServeHTTPWithSecret(t *testing.T) {
+	dbService := &mocks.DBService{}
+	httpService := &mocks.HttpService{}
+
+	handler = New(HandlerConfig{
+		DBService:       dbService,
+		HTTPService:     httpService,
+		Lang:            lang,
+		DefaultLanguage: "ru",
+		Token:           botToken,
+		ColsCount:       2,
+		Secret:          "s3cr3t",
+	})
+
+	user := golearn.User{
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Mode:     golearn.ModePicking,
+	}
+
+	update := &golearn.Update{
+		ChatID:   "177374215",
+		UserID:   "177374215",
+		Username: "sergeiten",
+		Name:     "Sergei",
+		Message:  "/start",
+	}
+
+	httpService.On("Parse", mock.Anything).Return(update, nil)
+	dbService.On("ExistUser", mock.Anything, user).Return(true, nil)
+	dbService.On("GetUser", mock.Anything, user.UserID).Return(user, nil)
+	httpService.On("Send", mock.Anything, update, lang["welcome"], mock.Anything).Return(nil)
+
+	req := httptest.NewRequest("POST", WebhookPath(botToken), strings.NewReader("{}"))
+	req.Header.Set(SecretHeader, "s3cr3t")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	dbService.AssertExpectations(t)
+	httpService.AssertExpectations(t)
+}
@@ -0,0 +1,104 @@
+package golearn
+
+import "strings"
+
+// VariantsDelimiter separates accepted variants of word or translation in source sheet,
+// e.g. "идти; ходить".
+const VariantsDelimiter = ";"
+
+// VariantsSeparator joins accepted variants shown to user.
+const VariantsSeparator = "; "
+
+// ParseVariants returns accepted variants separated by VariantsDelimiter.
+// Variants are trimmed, empty and repeated ones are skipped.
+func ParseVariants(s string) []string {
+	var variants []string
+
+	seen := map[string]bool{}
+	for _, v := range strings.Split(s, VariantsDelimiter) {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		variants = append(variants, v)
+	}
+
+	return variants
+}
+
+// NewRow returns row of passed word and translate which may contain several accepted
+// variants. The first variant is the main one and is saved as Word and Translate.
+func NewRow(word string, translate string, category string) Row {
+	spellings := ParseVariants(word)
+	translations := ParseVariants(translate)
+
+	row := Row{
+		Category: category,
+	}
+
+	if len(spellings) > 0 {
+		row.Word = spellings[0]
+	}
+	if len(translations) > 0 {
+		row.Translate = translations[0]
+	}
+
+	// single variant is saved as Word and Translate only
+	if len(spellings) > 1 {
+		row.Spellings = spellings
+	}
+	if len(translations) > 1 {
+		row.Translations = translations
+	}
+
+	return row
+}
+
+// WordVariants returns all accepted spellings of the word.
+func (r Row) WordVariants() []string {
+	if len(r.Spellings) > 0 {
+		return r.Spellings
+	}
+	return []string{r.Word}
+}
+
+// TranslateVariants returns all accepted translations of the word.
+func (r Row) TranslateVariants() []string {
+	if len(r.Translations) > 0 {
+		return r.Translations
+	}
+	return []string{r.Translate}
+}
+
+// MatchAny returns the best verdict of answer matched against every accepted variant.
+func MatchAny(m Matcher, variants []string, answer string) Verdict {
+	verdict := VerdictWrong
+
+	for _, v := range variants {
+		switch m.Match(v, answer) {
+		case VerdictExact:
+			return VerdictExact
+		case VerdictClose:
+			verdict = VerdictClose
+		}
+	}
+
+	return verdict
+}
+
+// ClosestVariant returns accepted variant which is the most similar to answer.
+func ClosestVariant(variants []string, answer string) string {
+	closest := ""
+	best := -1.0
+
+	for _, v := range variants {
+		similarity := JamoSimilarity(v, answer)
+		if similarity > best {
+			closest = v
+			best = similarity
+		}
+	}
+
+	return closest
+}
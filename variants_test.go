@@ -0,0 +1,90 @@
+package golearn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVariants(t *testing.T) {
+	testCases := map[string]struct {
+		Input    string
+		Expected []string
+	}{
+		"single variant":                {Input: "идти", Expected: []string{"идти"}},
+		"several variants":              {Input: "идти; ходить", Expected: []string{"идти", "ходить"}},
+		"spaces are trimmed":            {Input: " идти ;ходить ", Expected: []string{"идти", "ходить"}},
+		"empty variants are skipped":    {Input: "идти;; ;ходить;", Expected: []string{"идти", "ходить"}},
+		"repeated variants are skipped": {Input: "идти; идти", Expected: []string{"идти"}},
+		"empty string":                  {Input: "", Expected: nil},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, ParseVariants(tc.Input))
+		})
+	}
+}
+
+func TestNewRow(t *testing.T) {
+	testCases := map[string]struct {
+		Word      string
+		Translate string
+		Expected  Row
+	}{
+		"single variants": {
+			Word:      "가다",
+			Translate: "идти",
+			Expected: Row{
+				Word:      "가다",
+				Translate: "идти",
+				Category:  "verbs",
+			},
+		},
+		"several translations": {
+			Word:      "가다",
+			Translate: "идти; ходить",
+			Expected: Row{
+				Word:         "가다",
+				Translate:    "идти",
+				Category:     "verbs",
+				Translations: []string{"идти", "ходить"},
+			},
+		},
+		"several spellings": {
+			Word:      "짜장면; 자장면",
+			Translate: "чачжанмён",
+			Expected: Row{
+				Word:      "짜장면",
+				Translate: "чачжанмён",
+				Category:  "verbs",
+				Spellings: []string{"짜장면", "자장면"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, NewRow(tc.Word, tc.Translate, "verbs"))
+		})
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	variants := []string{"идти", "ходить"}
+	matcher := NewMatcher(DefaultMaxDistance)
+
+	assert.Equal(t, VerdictExact, MatchAny(matcher, variants, "ходить"))
+	assert.Equal(t, VerdictClose, MatchAny(matcher, variants, "ходит"))
+	assert.Equal(t, VerdictWrong, MatchAny(matcher, variants, "бежать"))
+	assert.Equal(t, VerdictExact, MatchAny(ExactMatcher{}, variants, "идти"))
+	assert.Equal(t, VerdictWrong, MatchAny(ExactMatcher{}, variants, "Идти"))
+}
+
+func TestClosestVariant(t *testing.T) {
+	variants := []string{"짜장면", "자장면"}
+
+	assert.Equal(t, "자장면", ClosestVariant(variants, "자장멍"))
+	assert.Equal(t, "짜장면", ClosestVariant(variants, "짜장"))
+	assert.Equal(t, "", ClosestVariant(nil, "짜장"))
+}